@@ -0,0 +1,137 @@
+package systemctl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// allowedFlags lists the systemctl flags accepted as additional arguments
+// in strict mode. Entries ending in "=" take a value, which must be given
+// in the same argument. Flags which change the host, machine, root
+// directory or service manager being talked to, such as --global, and
+// flags with side effects beyond the command, such as --firmware-setup,
+// are deliberately missing.
+var allowedFlags = []string{
+	"--after",
+	"--all",
+	"--before",
+	"--check-inhibitors=",
+	"--dry-run",
+	"--failed",
+	"--force",
+	"--full",
+	"--job-mode=",
+	"--kill-value=",
+	"--kill-whom=",
+	"--legend=",
+	"--lines=",
+	"--marked",
+	"--no-ask-password",
+	"--no-block",
+	"--no-legend",
+	"--no-pager",
+	"--no-reload",
+	"--no-warn",
+	"--no-wall",
+	"--now",
+	"--output=",
+	"--plain",
+	"--preset-mode=",
+	"--property=",
+	"--quiet",
+	"--recursive",
+	"--reverse",
+	"--runtime",
+	"--show-types",
+	"--signal=",
+	"--state=",
+	"--timestamp=",
+	"--type=",
+	"--value",
+	"--wait",
+	"--what=",
+	"--when=",
+	"-a",
+	"-f",
+	"-l",
+	"-q",
+	"-r",
+}
+
+// checkFlags returns ErrFlagNotAllowed for the first argument which is not
+// in allowedFlags.
+func checkFlags(args []string) error {
+	for _, arg := range args {
+		allowed := slices.ContainsFunc(allowedFlags, func(flag string) bool {
+			if strings.HasSuffix(flag, "=") {
+				return strings.HasPrefix(arg, flag) && len(arg) > len(flag)
+			}
+			return arg == flag
+		})
+		if !allowed {
+			return fmt.Errorf("%q: %w", arg, ErrFlagNotAllowed)
+		}
+	}
+	return nil
+}
+
+// validateUnitArg checks a unit argument before it is passed to systemctl.
+// Besides unit names, with or without a type suffix, systemctl accepts
+// shell-style patterns and absolute paths of devices and mount points.
+// Names starting with "-" must carry a unit type suffix, as in -.mount,
+// so that flags such as "--now" are never mistaken for units.
+func validateUnitArg(unit string) error {
+	switch {
+	case strings.HasPrefix(unit, "/"):
+		if strings.ContainsAny(unit, "\x00\n") {
+			return fmt.Errorf("%q contains invalid characters: %w", unit, ErrInvalidName)
+		}
+		return nil
+	case strings.HasPrefix(unit, "-") && !HasValidUnitSuffix(unit):
+		return fmt.Errorf("%q looks like a flag: %w", unit, ErrInvalidName)
+	case isGlob(unit):
+		for i := 0; i < len(unit); i++ {
+			if !isUnitChar(unit[i]) && !strings.ContainsRune("@*?[]!^", rune(unit[i])) {
+				return fmt.Errorf("%q contains invalid characters: %w", unit, ErrInvalidName)
+			}
+		}
+		return nil
+	default:
+		return ValidateUnitName(withDefaultType(unit, "service"))
+	}
+}
+
+// prepareUnitArgs works like prepareArgs for commands taking unit names,
+// but checks its input first: units must pass validateUnitArg, and if
+// Options.Strict is set, args may only contain flags from allowedFlags.
+// Flags are chosen by the library and are not checked, while Options.Flags
+// must be supported by the command (see flagArgs) and follow them.
+//
+// The arguments are ordered units, flags, args. If a unit starts with "-",
+// the units are moved behind a "--" instead, so systemctl doesn't parse
+// them as options.
+func prepareUnitArgs(base string, opts Options, units []string, flags []string, args []string) ([]string, error) {
+	for _, unit := range units {
+		if err := validateUnitArg(unit); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Strict {
+		if err := checkFlags(args); err != nil {
+			return nil, err
+		}
+	}
+	typed, err := flagArgs(base, opts.Flags)
+	if err != nil {
+		return nil, err
+	}
+	flags = slices.Concat(flags, typed)
+	dashed := slices.ContainsFunc(units, func(unit string) bool {
+		return strings.HasPrefix(unit, "-")
+	})
+	if !dashed {
+		return prepareArgs(base, opts, slices.Concat(units, flags, args)...), nil
+	}
+	return slices.Concat(prepareArgs(base, opts, slices.Concat(flags, args)...), []string{"--"}, units), nil
+}
@@ -0,0 +1,38 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUnitArgInjection(t *testing.T) {
+	logFile := fakeSystemctl(t, `exit 0`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := Stop(ctx, "--now", Options{}); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Stop(--now) error is %v, but should have been %v", err, ErrInvalidName)
+	}
+	if _, err := EnableWithChanges(ctx, "nginx.service", Options{Strict: true}, "--root=/mnt"); !errors.Is(err, ErrFlagNotAllowed) {
+		t.Errorf("strict Enable error is %v, but should have been %v", err, ErrFlagNotAllowed)
+	}
+	if err := Restart(ctx, "-.mount", Options{}, "--no-block"); err != nil {
+		t.Errorf("Restart(-.mount) returned error: %v", err)
+	}
+	if err := Start(ctx, "nginx", Options{Strict: true}, "--no-block"); err != nil {
+		t.Errorf("strict Start returned error: %v", err)
+	}
+
+	wantArgs := [][]string{
+		{"restart", "--system", "--no-block", "--", "-.mount"},
+		{"start", "--system", "nginx", "--no-block"},
+	}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
@@ -0,0 +1,121 @@
+package systemctl
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateUnitArg(t *testing.T) {
+	for _, unit := range []string{
+		"nginx",
+		"nginx.service",
+		"getty@tty1.service",
+		"-.mount",
+		"-.slice",
+		"nginx@*.service",
+		"nginx*",
+		"[ab]*.socket",
+		"/dev/sda",
+		"/home",
+	} {
+		if err := validateUnitArg(unit); err != nil {
+			t.Errorf("validateUnitArg(%q) returned error: %v", unit, err)
+		}
+	}
+	for _, unit := range []string{
+		"",
+		"--now",
+		"-H",
+		"--host=evil",
+		"-H evil.service",
+		"foo bar",
+		"nginx;reboot",
+		"nginx*; reboot",
+		"/dev/sda\n--now",
+	} {
+		if err := validateUnitArg(unit); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("validateUnitArg(%q) error is %v, but should have been %v", unit, err, ErrInvalidName)
+		}
+	}
+}
+
+func TestCheckFlags(t *testing.T) {
+	if err := checkFlags([]string{"--now", "-q", "--no-block", "--job-mode=replace", "--signal=SIGHUP"}); err != nil {
+		t.Errorf("checkFlags returned error: %v", err)
+	}
+	for _, arg := range []string{
+		"-H",
+		"--host=evil",
+		"--machine=root@.host",
+		"--root=/mnt",
+		"--user",
+		"--global",
+		"--firmware-setup",
+		"--job-mode",
+		"--job-mode=",
+		"nginx.service",
+		"--nowx",
+	} {
+		if err := checkFlags([]string{"--now", arg}); !errors.Is(err, ErrFlagNotAllowed) {
+			t.Errorf("checkFlags(%q) error is %v, but should have been %v", arg, err, ErrFlagNotAllowed)
+		}
+	}
+}
+
+func TestPrepareUnitArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		units    []string
+		flags    []string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "unit flags and args",
+			units:    []string{"nginx.service"},
+			flags:    []string{"--property", "MainPID"},
+			args:     []string{"--no-pager"},
+			expected: []string{"show", "--system", "nginx.service", "--property", "MainPID", "--no-pager"},
+		},
+		{
+			name:     "unit starting with a dash",
+			opts:     Options{UserMode: true},
+			units:    []string{"-.mount", "home.mount"},
+			args:     []string{"--now"},
+			expected: []string{"show", "--user", "--now", "--", "-.mount", "home.mount"},
+		},
+		{
+			name:     "strict with allowed flags",
+			opts:     Options{Strict: true},
+			units:    []string{"nginx"},
+			args:     []string{"--no-block"},
+			expected: []string{"show", "--system", "nginx", "--no-block"},
+		},
+		{
+			name:     "unchecked args outside strict mode",
+			units:    []string{"nginx"},
+			args:     []string{"-H", "host"},
+			expected: []string{"show", "--system", "nginx", "-H", "host"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prepareUnitArgs("show", tt.opts, tt.units, tt.flags, tt.args)
+			if err != nil {
+				t.Fatalf("prepareUnitArgs returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("prepareUnitArgs = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := prepareUnitArgs("start", Options{}, []string{"--now"}, nil, nil); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("error is %v, but should have been %v", err, ErrInvalidName)
+	}
+	if _, err := prepareUnitArgs("start", Options{Strict: true}, []string{"nginx"}, nil, []string{"-H", "host"}); !errors.Is(err, ErrFlagNotAllowed) {
+		t.Errorf("error is %v, but should have been %v", err, ErrFlagNotAllowed)
+	}
+}
@@ -0,0 +1,134 @@
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/taigrr/systemctl/properties"
+)
+
+// GetActiveStates returns the active state ("active", "inactive",
+// "failed", "activating", ...) of each of the given units, keyed by the
+// names passed in, using a single `systemctl is-active` call.
+//
+// Units which do not exist are reported as "inactive", as systemctl does.
+// Use it instead of calling IsActive or IsFailed for many units. Patterns
+// are rejected with ErrInvalidName; see ExpandUnits.
+func GetActiveStates(ctx context.Context, units []string, opts Options) (map[string]string, error) {
+	if len(units) == 0 {
+		return map[string]string{}, nil
+	}
+	if err := rejectPatterns(units); err != nil {
+		return nil, err
+	}
+	args, err := prepareUnitArgs("is-active", opts, units, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	stdout, stderr, _, err := execute(ctx, args)
+	// is-active exits non-zero unless every unit is active, so only treat
+	// it as a failure if systemctl complained or the output is incomplete.
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if errors.Is(err, ErrExecTimeout) || filterErr(stderr) != nil {
+		return nil, err
+	}
+	if stdout == "" || len(lines) != len(units) {
+		if err == nil {
+			err = ErrUnspecified
+		}
+		return nil, fmt.Errorf("expected %d states from is-active, got %d: %w", len(units), len(lines), err)
+	}
+	states := make(map[string]string, len(units))
+	for i, unit := range units {
+		states[unit] = lines[i]
+	}
+	return states, nil
+}
+
+// GetUnitFileStates returns the unit file state ("enabled", "disabled",
+// "static", "masked", ...) of each of the given units, keyed by the names
+// passed in, using a single `systemctl show` call. Units without a unit
+// file are reported with an empty state.
+func GetUnitFileStates(ctx context.Context, units []string, opts Options) (map[string]string, error) {
+	values, err := ShowUnits(ctx, units, []properties.Property{properties.UnitFileState}, opts.withoutFlags())
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]string, len(values))
+	for unit, props := range values {
+		states[unit] = props[properties.UnitFileState]
+	}
+	return states, nil
+}
+
+// ShowUnits reads the given properties of several units in a single
+// `systemctl show` call. The result is keyed by the unit names passed in,
+// and every requested property is present, possibly empty. Patterns are
+// rejected with ErrInvalidName; see ExpandUnits.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func ShowUnits(ctx context.Context, units []string, props []properties.Property, opts Options, args ...string) (map[string]map[properties.Property]string, error) {
+	if len(units) == 0 {
+		return map[string]map[properties.Property]string{}, nil
+	}
+	if err := rejectPatterns(units); err != nil {
+		return nil, err
+	}
+	// Id is never empty, so every unit produces a block even if all the
+	// requested properties are empty and left out by systemctl.
+	names := []string{string(properties.Id)}
+	for _, p := range props {
+		names = append(names, string(p))
+	}
+	a, err := prepareUnitArgs("show", opts, units, []string{"--property=" + strings.Join(names, ",")}, args)
+	if err != nil {
+		return nil, err
+	}
+	if err := requirePropertyVersions(ctx, props...); err != nil {
+		return nil, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	if err != nil {
+		return nil, errors.Join(err, filterErr(stderr))
+	}
+	blocks := splitShowBlocks(stdout)
+	if len(blocks) != len(units) {
+		return nil, fmt.Errorf("expected %d units from show, got %d: %w", len(units), len(blocks), ErrUnspecified)
+	}
+	result := make(map[string]map[properties.Property]string, len(units))
+	for i, unit := range units {
+		values := make(map[properties.Property]string, len(props))
+		for _, p := range props {
+			values[p] = blocks[i][p]
+		}
+		result[unit] = values
+	}
+	return result, nil
+}
+
+// rejectPatterns returns ErrInvalidName for the first unit which is a
+// pattern. The batched queries map their output to units by position, which
+// breaks if a pattern matches no unit or several.
+func rejectPatterns(units []string) error {
+	for _, unit := range units {
+		if isGlob(unit) {
+			return fmt.Errorf("%q is a pattern: %w", unit, ErrInvalidName)
+		}
+	}
+	return nil
+}
+
+// splitShowBlocks splits the output of `systemctl show` for several units,
+// which separates the properties of each unit with an empty line.
+func splitShowBlocks(stdout string) []map[properties.Property]string {
+	var blocks []map[properties.Property]string
+	for _, block := range strings.Split(strings.TrimSpace(stdout), "\n\n") {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+		blocks = append(blocks, parseProperties(block))
+	}
+	return blocks
+}
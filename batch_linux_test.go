@@ -0,0 +1,109 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/taigrr/systemctl/properties"
+)
+
+func TestGetActiveStates(t *testing.T) {
+	logFile := fakeSystemctl(t, `printf 'active\nfailed\ninactive\n'
+exit 3`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	units := []string{"nginx.service", "broken.service", "missing"}
+	got, err := GetActiveStates(ctx, units, Options{})
+	if err != nil {
+		t.Fatalf("GetActiveStates returned error: %v", err)
+	}
+	want := map[string]string{"nginx.service": "active", "broken.service": "failed", "missing": "inactive"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetActiveStates = %v, want %v", got, want)
+	}
+	wantArgs := [][]string{{"is-active", "--system", "nginx.service", "broken.service", "missing"}}
+	if calls := fakeInvocations(t, logFile); !reflect.DeepEqual(calls, wantArgs) {
+		t.Errorf("invocations = %v, want %v", calls, wantArgs)
+	}
+}
+
+func TestGetActiveStatesErrors(t *testing.T) {
+	fakeSystemctl(t, `echo "Failed to connect to bus: No such file or directory" >&2
+exit 1`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := GetActiveStates(ctx, []string{"a.service", "b.service"}, Options{UserMode: true}); !errors.Is(err, ErrBusFailure) {
+		t.Errorf("error is %v, but should have been %v", err, ErrBusFailure)
+	}
+
+	fakeSystemctl(t, `echo active`)
+	if _, err := GetActiveStates(ctx, []string{"a.service", "b.service"}, Options{}); !errors.Is(err, ErrUnspecified) {
+		t.Errorf("error is %v, but should have been %v", err, ErrUnspecified)
+	}
+}
+
+func TestBatchRejectsPatterns(t *testing.T) {
+	logFile := fakeSystemctl(t, ``)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	units := []string{"nginx.service", "app@*.service"}
+	if _, err := GetActiveStates(ctx, units, Options{}); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("GetActiveStates error is %v, but should have been %v", err, ErrInvalidName)
+	}
+	if _, err := ShowUnits(ctx, units, []properties.Property{properties.MainPID}, Options{}); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("ShowUnits error is %v, but should have been %v", err, ErrInvalidName)
+	}
+	if got := fakeInvocations(t, logFile); len(got) != 0 {
+		t.Errorf("invocations = %v, want none", got)
+	}
+}
+
+func TestShowUnits(t *testing.T) {
+	logFile := fakeSystemctl(t, `printf 'Id=a.service\nUnitFileState=enabled\nMainPID=42\n\nId=b.service\nMainPID=0\n'`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	props := []properties.Property{properties.UnitFileState, properties.MainPID}
+	got, err := ShowUnits(ctx, []string{"a", "b.service"}, props, Options{})
+	if err != nil {
+		t.Fatalf("ShowUnits returned error: %v", err)
+	}
+	want := map[string]map[properties.Property]string{
+		"a":         {properties.UnitFileState: "enabled", properties.MainPID: "42"},
+		"b.service": {properties.UnitFileState: "", properties.MainPID: "0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ShowUnits = %v, want %v", got, want)
+	}
+	wantArgs := [][]string{{"show", "--system", "a", "b.service", "--property=Id,UnitFileState,MainPID"}}
+	if calls := fakeInvocations(t, logFile); !reflect.DeepEqual(calls, wantArgs) {
+		t.Errorf("invocations = %v, want %v", calls, wantArgs)
+	}
+
+	states, err := GetUnitFileStates(ctx, []string{"a", "b.service"}, Options{})
+	if err != nil {
+		t.Fatalf("GetUnitFileStates returned error: %v", err)
+	}
+	if want := map[string]string{"a": "enabled", "b.service": ""}; !reflect.DeepEqual(states, want) {
+		t.Errorf("GetUnitFileStates = %v, want %v", states, want)
+	}
+}
+
+func TestShowUnitsMismatch(t *testing.T) {
+	fakeSystemctl(t, `printf 'Id=a.service\n'`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := ShowUnits(ctx, []string{"a.service", "b.service"}, []properties.Property{properties.MainPID}, Options{})
+	if !errors.Is(err, ErrUnspecified) {
+		t.Errorf("error is %v, but should have been %v", err, ErrUnspecified)
+	}
+}
@@ -0,0 +1,24 @@
+package systemctl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/taigrr/systemctl/properties"
+)
+
+func TestSplitShowBlocks(t *testing.T) {
+	stdout := "Id=a.service\nActiveState=active\n\nId=b.service\nActiveState=failed\n\nId=c.service\n"
+	got := splitShowBlocks(stdout)
+	want := []map[properties.Property]string{
+		{properties.Id: "a.service", properties.ActiveState: "active"},
+		{properties.Id: "b.service", properties.ActiveState: "failed"},
+		{properties.Id: "c.service"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitShowBlocks = %v, want %v", got, want)
+	}
+	if got := splitShowBlocks(""); len(got) != 0 {
+		t.Errorf("splitShowBlocks(\"\") = %v, want none", got)
+	}
+}
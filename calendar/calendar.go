@@ -0,0 +1,700 @@
+// Package calendar parses systemd calendar event expressions and time spans,
+// as used by OnCalendar= and the various *Sec= settings, without shelling
+// out to systemd-analyze. See systemd.time(7) for the syntax.
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrSyntax is returned when an expression cannot be parsed.
+var ErrSyntax = errors.New("invalid calendar expression")
+
+const (
+	minYear = 1970
+	maxYear = 2199
+	usecPer = 1000000
+)
+
+var shorthands = map[string]string{
+	"minutely":     "*-*-* *:*:00",
+	"hourly":       "*-*-* *:00:00",
+	"daily":        "*-*-* 00:00:00",
+	"monthly":      "*-*-01 00:00:00",
+	"weekly":       "Mon *-*-* 00:00:00",
+	"yearly":       "*-01-01 00:00:00",
+	"annually":     "*-01-01 00:00:00",
+	"quarterly":    "*-01,04,07,10-01 00:00:00",
+	"semiannually": "*-01,07-01 00:00:00",
+}
+
+var weekdayNames = []struct {
+	short string
+	long  string
+}{
+	{"mon", "monday"},
+	{"tue", "tuesday"},
+	{"wed", "wednesday"},
+	{"thu", "thursday"},
+	{"fri", "friday"},
+	{"sat", "saturday"},
+	{"sun", "sunday"},
+}
+
+// component matches a single value, a range of values or a repetition of
+// values. Stop is -1 if the component is not a range, Repeat is 0 if the
+// component does not repeat.
+type component struct {
+	start  int
+	stop   int
+	repeat int
+}
+
+// field is a list of components, matching any value one of them matches.
+// An empty field is a wildcard.
+type field []component
+
+// Spec is a parsed calendar event expression.
+type Spec struct {
+	// weekdays is a bit mask with Monday as bit 0, or 0 for any weekday.
+	weekdays   int
+	year       field
+	month      field
+	day        field
+	endOfMonth bool
+	hour       field
+	minute     field
+	// second is in microseconds, to support fractional seconds.
+	second   field
+	location *time.Location
+}
+
+// Parse parses a calendar event expression such as "Mon..Fri 09:00",
+// "*-*-01 00:00:00 UTC" or "hourly". Expressions without a timezone are
+// evaluated in the local timezone.
+func Parse(expr string) (*Spec, error) {
+	tokens := strings.Fields(expr)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression: %w", ErrSyntax)
+	}
+	if normalized, ok := shorthands[strings.ToLower(tokens[0])]; ok {
+		tokens = append(strings.Fields(normalized), tokens[1:]...)
+	}
+
+	s := &Spec{location: time.Local}
+	i := 0
+	// Weekdays come first and may span several tokens ("Mon, Wed").
+	var weekdays string
+	for i < len(tokens) && isWeekdayList(tokens[i]) {
+		weekdays += tokens[i]
+		i++
+		if !strings.HasSuffix(weekdays, ",") {
+			break
+		}
+	}
+	if weekdays != "" {
+		mask, err := parseWeekdays(strings.TrimSuffix(weekdays, ","))
+		if err != nil {
+			return nil, err
+		}
+		s.weekdays = mask
+	}
+
+	var haveDate, haveTime bool
+	for ; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case !haveTime && strings.Contains(token, ":"):
+			if err := s.parseTime(token); err != nil {
+				return nil, err
+			}
+			haveTime = true
+		case !haveDate && !haveTime && (strings.ContainsAny(token, "-~") || isNumeric(token)):
+			if err := s.parseDate(token); err != nil {
+				return nil, err
+			}
+			haveDate = true
+		case i == len(tokens)-1:
+			loc, err := loadLocation(token)
+			if err != nil {
+				return nil, fmt.Errorf("unknown timezone %q: %w", token, ErrSyntax)
+			}
+			s.location = loc
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q: %w", token, expr, ErrSyntax)
+		}
+	}
+	if !haveTime {
+		s.hour = field{{0, -1, 0}}
+		s.minute = field{{0, -1, 0}}
+		s.second = field{{0, -1, 0}}
+	}
+	return s, nil
+}
+
+func loadLocation(name string) (*time.Location, error) {
+	if name == "UTC" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
+
+func isNumeric(token string) bool {
+	_, err := strconv.Atoi(token)
+	return err == nil
+}
+
+func isWeekdayList(token string) bool {
+	c := token[0]
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func parseWeekday(name string) (int, error) {
+	name = strings.ToLower(name)
+	for i, w := range weekdayNames {
+		if name == w.short || name == w.long {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday %q: %w", name, ErrSyntax)
+}
+
+func parseWeekdays(list string) (int, error) {
+	mask := 0
+	for _, item := range strings.Split(list, ",") {
+		from, to, isRange := strings.Cut(item, "..")
+		start, err := parseWeekday(from)
+		if err != nil {
+			return 0, err
+		}
+		stop := start
+		if isRange {
+			if stop, err = parseWeekday(to); err != nil {
+				return 0, err
+			}
+			if stop < start {
+				return 0, fmt.Errorf("weekday range %q runs backwards: %w", item, ErrSyntax)
+			}
+		}
+		for d := start; d <= stop; d++ {
+			mask |= 1 << d
+		}
+	}
+	return mask, nil
+}
+
+func (s *Spec) parseDate(token string) error {
+	var parts []string
+	if before, day, ok := strings.Cut(token, "~"); ok {
+		s.endOfMonth = true
+		parts = append(strings.Split(before, "-"), day)
+	} else {
+		parts = strings.Split(token, "-")
+	}
+	var err error
+	switch len(parts) {
+	case 3:
+		if s.year, err = parseField(parts[0], minYear, false); err != nil {
+			return err
+		}
+		for i, c := range s.year {
+			s.year[i].start = expandYear(c.start)
+			if c.stop >= 0 {
+				s.year[i].stop = expandYear(c.stop)
+			}
+		}
+		parts = parts[1:]
+	case 2:
+	default:
+		return fmt.Errorf("invalid date %q: %w", token, ErrSyntax)
+	}
+	if s.month, err = parseField(parts[0], 1, false); err != nil {
+		return err
+	}
+	if s.day, err = parseField(parts[1], 1, false); err != nil {
+		return err
+	}
+	return s.validateDate(token)
+}
+
+// expandYear maps two-digit years to 1970-2069, as systemd does.
+func expandYear(year int) int {
+	switch {
+	case year < 70:
+		return year + 2000
+	case year < 100:
+		return year + 1900
+	default:
+		return year
+	}
+}
+
+func (s *Spec) validateDate(token string) error {
+	checks := []struct {
+		f        field
+		min, max int
+	}{
+		{s.year, minYear, maxYear},
+		{s.month, 1, 12},
+		{s.day, 1, 31},
+	}
+	for _, c := range checks {
+		if !c.f.within(c.min, c.max) {
+			return fmt.Errorf("date %q out of range: %w", token, ErrSyntax)
+		}
+	}
+	return nil
+}
+
+func (s *Spec) parseTime(token string) error {
+	parts := strings.Split(token, ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return fmt.Errorf("invalid time %q: %w", token, ErrSyntax)
+	}
+	var err error
+	if s.hour, err = parseField(parts[0], 0, false); err != nil {
+		return err
+	}
+	if s.minute, err = parseField(parts[1], 0, false); err != nil {
+		return err
+	}
+	if len(parts) == 3 {
+		if s.second, err = parseField(parts[2], 0, true); err != nil {
+			return err
+		}
+	} else {
+		s.second = field{{0, -1, 0}}
+	}
+	if !s.hour.within(0, 23) || !s.minute.within(0, 59) || !s.second.within(0, 60*usecPer-1) {
+		return fmt.Errorf("time %q out of range: %w", token, ErrSyntax)
+	}
+	return nil
+}
+
+func (f field) within(min, max int) bool {
+	for _, c := range f {
+		if c.start < min || c.start > max || c.stop > max || (c.stop >= 0 && c.stop < c.start) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseField parses a comma separated list of values, ranges ("a..b") and
+// repetitions ("a/n", "a..b/n", "*/n"). A "*/n" repetition starts at min,
+// the smallest value of the field. If usec is set, values may have a
+// fractional part and are returned in microseconds.
+func parseField(text string, min int, usec bool) (field, error) {
+	if text == "*" {
+		return nil, nil
+	}
+	var f field
+	for _, item := range strings.Split(text, ",") {
+		value, rep, hasRepeat := strings.Cut(item, "/")
+		from, to, isRange := strings.Cut(value, "..")
+		c := component{stop: -1}
+		var err error
+		if from == "*" && !isRange && hasRepeat {
+			c.start = min
+		} else if c.start, err = parseNumber(from, usec); err != nil {
+			return nil, err
+		}
+		if isRange {
+			if c.stop, err = parseNumber(to, usec); err != nil {
+				return nil, err
+			}
+		}
+		if hasRepeat {
+			if c.repeat, err = parseNumber(rep, usec); err != nil {
+				return nil, err
+			}
+			if c.repeat == 0 {
+				return nil, fmt.Errorf("zero repetition in %q: %w", item, ErrSyntax)
+			}
+		}
+		f = append(f, c)
+	}
+	sort.Slice(f, func(i, j int) bool {
+		a, b := f[i], f[j]
+		if a.start != b.start {
+			return a.start < b.start
+		}
+		if a.stop != b.stop {
+			return a.stop < b.stop
+		}
+		return a.repeat < b.repeat
+	})
+	deduped := f[:1]
+	for _, c := range f[1:] {
+		if c != deduped[len(deduped)-1] {
+			deduped = append(deduped, c)
+		}
+	}
+	return deduped, nil
+}
+
+// parseNumber parses a non-negative integer. If usec is set, a fractional
+// part is allowed and the value is returned in microseconds, rounded to
+// the nearest microsecond.
+func parseNumber(text string, usec bool) (int, error) {
+	whole, frac, hasFrac := strings.Cut(text, ".")
+	if whole == "" || strings.Trim(whole, "0123456789") != "" || (hasFrac && !usec) {
+		return 0, fmt.Errorf("invalid number %q: %w", text, ErrSyntax)
+	}
+	n, err := strconv.Atoi(whole)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q: %w", text, ErrSyntax)
+	}
+	if !usec {
+		return n, nil
+	}
+	n *= usecPer
+	if hasFrac {
+		if frac == "" || strings.Trim(frac, "0123456789") != "" {
+			return 0, fmt.Errorf("invalid number %q: %w", text, ErrSyntax)
+		}
+		digits := (frac + "000000")[:6]
+		f, _ := strconv.Atoi(digits)
+		if len(frac) > 6 && frac[6] >= '5' {
+			f++
+		}
+		n += f
+	}
+	return n, nil
+}
+
+// String returns the normalized form of the expression, as printed by
+// `systemd-analyze calendar`.
+func (s *Spec) String() string {
+	var parts []string
+	if s.weekdays != 0 {
+		parts = append(parts, formatWeekdays(s.weekdays))
+	}
+	daySep := "-"
+	if s.endOfMonth {
+		daySep = "~"
+	}
+	parts = append(parts,
+		s.year.format(4, false)+"-"+s.month.format(2, false)+daySep+s.day.format(2, false),
+		s.hour.format(2, false)+":"+s.minute.format(2, false)+":"+s.second.format(2, true),
+	)
+	if s.location != time.Local {
+		parts = append(parts, s.location.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatWeekdays(mask int) string {
+	var items []string
+	for d := 0; d < 7; d++ {
+		if mask&(1<<d) == 0 {
+			continue
+		}
+		end := d
+		for end+1 < 7 && mask&(1<<(end+1)) != 0 {
+			end++
+		}
+		name := strings.ToUpper(weekdayNames[d].short[:1]) + weekdayNames[d].short[1:]
+		switch {
+		case end-d >= 2:
+			last := strings.ToUpper(weekdayNames[end].short[:1]) + weekdayNames[end].short[1:]
+			items = append(items, name+".."+last)
+			d = end
+		default:
+			items = append(items, name)
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+func (f field) format(width int, usec bool) string {
+	if len(f) == 0 {
+		return "*"
+	}
+	number := func(n int, width int) string {
+		if !usec {
+			return fmt.Sprintf("%0*d", width, n)
+		}
+		out := fmt.Sprintf("%0*d", width, n/usecPer)
+		if n%usecPer != 0 {
+			out += fmt.Sprintf(".%06d", n%usecPer)
+		}
+		return out
+	}
+	items := make([]string, len(f))
+	for i, c := range f {
+		item := number(c.start, width)
+		if c.stop >= 0 {
+			item += ".." + number(c.stop, width)
+		}
+		if c.repeat > 0 {
+			item += "/" + number(c.repeat, 1)
+		}
+		items[i] = item
+	}
+	return strings.Join(items, ",")
+}
+
+// next returns the smallest value in [v, max] matched by the field.
+func (f field) next(v int, max int) (int, bool) {
+	if v > max {
+		return 0, false
+	}
+	if len(f) == 0 {
+		return v, true
+	}
+	best, found := 0, false
+	for _, c := range f {
+		limit := max
+		if c.stop >= 0 && c.stop < limit {
+			limit = c.stop
+		}
+		var candidate int
+		switch {
+		case v <= c.start:
+			candidate = c.start
+		case c.repeat > 0:
+			candidate = c.start + (v-c.start+c.repeat-1)/c.repeat*c.repeat
+		case c.stop >= 0:
+			candidate = v
+		default:
+			continue
+		}
+		if candidate > limit {
+			continue
+		}
+		if !found || candidate < best {
+			best, found = candidate, true
+		}
+	}
+	return best, found
+}
+
+// wholeSeconds reports whether a seconds field only matches whole seconds.
+func (f field) wholeSeconds() bool {
+	for _, c := range f {
+		if c.start%usecPer != 0 || (c.stop >= 0 && c.stop%usecPer != 0) || c.repeat%usecPer != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether the field matches v exactly.
+func (f field) matches(v int, max int) bool {
+	n, ok := f.next(v, max)
+	return ok && n == v
+}
+
+// matchesFromEnd matches a day counted from the end of the month, where 1
+// is the last day. Repetitions count towards the end of the month.
+func (f field) matchesFromEnd(k int) bool {
+	if len(f) == 0 {
+		return true
+	}
+	for _, c := range f {
+		switch {
+		case c.stop >= 0:
+			lo, hi := c.stop, c.start
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			if k >= lo && k <= hi && (c.repeat == 0 || (hi-k)%c.repeat == 0) {
+				return true
+			}
+		case c.repeat > 0:
+			if k <= c.start && (c.start-k)%c.repeat == 0 {
+				return true
+			}
+		case k == c.start:
+			return true
+		}
+	}
+	return false
+}
+
+func daysIn(year int, month int) int {
+	return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func (s *Spec) dayMatches(year int, month int, day int) bool {
+	if s.endOfMonth {
+		if !s.day.matchesFromEnd(daysIn(year, month) - day + 1) {
+			return false
+		}
+	} else if !s.day.matches(day, 31) {
+		return false
+	}
+	if s.weekdays == 0 {
+		return true
+	}
+	weekday := (int(time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).Weekday()) + 6) % 7
+	return s.weekdays&(1<<weekday) != 0
+}
+
+// Next returns the first time strictly after the given time which matches
+// the expression, or the zero Time if there is none.
+//
+// Local times skipped by a daylight saving time change never match, and
+// local times repeated by one match both times they occur.
+func (s *Spec) Next(after time.Time) time.Time {
+	// Without fractional seconds the expression can only match whole
+	// seconds, so there is no point in trying every microsecond.
+	step := time.Microsecond
+	if s.second.wholeSeconds() {
+		step = time.Second
+	}
+	// Start from the earliest wall clock time after may be shown as, so
+	// the repetition of the hour after a daylight saving time change is
+	// searched as well.
+	offset := 0
+	for i, probe := range []time.Time{after.Add(-12 * time.Hour), after, after.Add(12 * time.Hour)} {
+		if _, o := probe.In(s.location).Zone(); i == 0 || o < offset {
+			offset = o
+		}
+	}
+	wall := after.UTC().Add(time.Duration(offset) * time.Second).Truncate(step).Add(step)
+	for {
+		wall = s.nextWallClock(wall)
+		if wall.IsZero() {
+			return time.Time{}
+		}
+		for _, t := range s.instants(wall) {
+			if t.After(after) {
+				return t
+			}
+		}
+		wall = wall.Add(step)
+	}
+}
+
+// instants returns the times at which the clock in the expression's
+// timezone shows the given wall clock time, which is passed in UTC. There
+// are none in the gap of a daylight saving time change, and two in the
+// hour repeated by one.
+func (s *Spec) instants(wall time.Time) []time.Time {
+	near := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), s.location)
+	var times []time.Time
+	for _, probe := range []time.Time{near.Add(-12 * time.Hour), near.Add(12 * time.Hour)} {
+		_, offset := probe.Zone()
+		t := wall.Add(-time.Duration(offset) * time.Second).In(s.location)
+		shown := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+		if shown.Equal(wall) && (len(times) == 0 || !times[0].Equal(t)) {
+			times = append(times, t)
+		}
+	}
+	if len(times) == 2 && times[1].Before(times[0]) {
+		times[0], times[1] = times[1], times[0]
+	}
+	return times
+}
+
+// nextWallClock returns the first wall clock time at or after the given
+// one which matches the expression, ignoring the timezone. Wall clock times
+// are passed in UTC. It returns the zero Time if there is none.
+func (s *Spec) nextWallClock(t time.Time) time.Time {
+	year, month, day := t.Year(), int(t.Month()), t.Day()
+	hour, minute := t.Hour(), t.Minute()
+	usec := t.Second()*usecPer + t.Nanosecond()/1000
+
+	for year <= maxYear {
+		y, ok := s.year.next(year, maxYear)
+		if !ok {
+			return time.Time{}
+		}
+		if y != year {
+			year, month, day, hour, minute, usec = y, 1, 1, 0, 0, 0
+		}
+		m, ok := s.month.next(month, 12)
+		if !ok {
+			year, month, day, hour, minute, usec = year+1, 1, 1, 0, 0, 0
+			continue
+		}
+		if m != month {
+			month, day, hour, minute, usec = m, 1, 0, 0, 0
+		}
+		d := day
+		for d <= daysIn(year, month) && !s.dayMatches(year, month, d) {
+			d++
+		}
+		if d > daysIn(year, month) {
+			month, day, hour, minute, usec = month+1, 1, 0, 0, 0
+			if month > 12 {
+				year, month = year+1, 1
+			}
+			continue
+		}
+		if d != day {
+			day, hour, minute, usec = d, 0, 0, 0
+		}
+		h, ok := s.hour.next(hour, 23)
+		if !ok {
+			day, hour, minute, usec = day+1, 0, 0, 0
+			if day > daysIn(year, month) {
+				month, day = month+1, 1
+				if month > 12 {
+					year, month = year+1, 1
+				}
+			}
+			continue
+		}
+		if h != hour {
+			hour, minute, usec = h, 0, 0
+		}
+		mi, ok := s.minute.next(minute, 59)
+		if !ok {
+			hour, minute, usec = hour+1, 0, 0
+			if hour > 23 {
+				day, hour = day+1, 0
+				if day > daysIn(year, month) {
+					month, day = month+1, 1
+					if month > 12 {
+						year, month = year+1, 1
+					}
+				}
+			}
+			continue
+		}
+		if mi != minute {
+			minute, usec = mi, 0
+		}
+		sec, ok := s.second.next(usec, 60*usecPer-1)
+		if !ok {
+			minute, usec = minute+1, 0
+			if minute > 59 {
+				hour, minute = hour+1, 0
+				if hour > 23 {
+					day, hour = day+1, 0
+					if day > daysIn(year, month) {
+						month, day = month+1, 1
+						if month > 12 {
+							year, month = year+1, 1
+						}
+					}
+				}
+			}
+			continue
+		}
+		return time.Date(year, time.Month(month), day, hour, minute, sec/usecPer, sec%usecPer*1000, time.UTC)
+	}
+	return time.Time{}
+}
+
+// NextN returns up to n consecutive times after the given time which
+// match the expression, like `systemd-analyze calendar --iterations=N`.
+func (s *Spec) NextN(after time.Time, n int) []time.Time {
+	times := make([]time.Time, 0, n)
+	for len(times) < n {
+		after = s.Next(after)
+		if after.IsZero() {
+			break
+		}
+		times = append(times, after)
+	}
+	return times
+}
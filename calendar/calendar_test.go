@@ -0,0 +1,258 @@
+package calendar
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// normalized lists input expressions and their normalized forms as given in
+// the "Calendar Events" section of systemd.time(7).
+var normalized = []struct {
+	expr string
+	want string
+}{
+	{"Sat,Thu,Mon..Wed,Sat..Sun", "Mon..Thu,Sat,Sun *-*-* 00:00:00"},
+	{"Mon,Sun 12-*-* 2,1:23", "Mon,Sun 2012-*-* 01,02:23:00"},
+	{"Wed *-1", "Wed *-*-01 00:00:00"},
+	{"Wed..Wed,Wed *-1", "Wed *-*-01 00:00:00"},
+	{"Wed, 17:48", "Wed *-*-* 17:48:00"},
+	{"Wed..Sat,Tue 12-10-15 1:2:3", "Tue..Sat 2012-10-15 01:02:03"},
+	{"*-*-7 0:0:0", "*-*-07 00:00:00"},
+	{"10-15", "*-10-15 00:00:00"},
+	{"monday *-12-* 17:00", "Mon *-12-* 17:00:00"},
+	{"Mon,Fri *-*-3,1,2 *:30:45", "Mon,Fri *-*-01,02,03 *:30:45"},
+	{"12,14,13,12:20,10,30", "*-*-* 12,13,14:10,20,30:00"},
+	{"12..14:10,20,30", "*-*-* 12..14:10,20,30:00"},
+	{"mon,fri *-1/2-1,3 *:30:45", "Mon,Fri *-01/2-01,03 *:30:45"},
+	{"03-05 08:05:40", "*-03-05 08:05:40"},
+	{"08:05:40", "*-*-* 08:05:40"},
+	{"05:40", "*-*-* 05:40:00"},
+	{"Sat,Sun 12-05 08:05:40", "Sat,Sun *-12-05 08:05:40"},
+	{"Sat,Sun 08:05:40", "Sat,Sun *-*-* 08:05:40"},
+	{"2003-03-05 05:40", "2003-03-05 05:40:00"},
+	{"05:40:23.4200004/3.1700005", "*-*-* 05:40:23.420000/3.170001"},
+	{"2003-02..04-05", "2003-02..04-05 00:00:00"},
+	{"2003-03-05 05:40 UTC", "2003-03-05 05:40:00 UTC"},
+	{"2003-03-05", "2003-03-05 00:00:00"},
+	{"03-05", "*-03-05 00:00:00"},
+	{"hourly", "*-*-* *:00:00"},
+	{"daily", "*-*-* 00:00:00"},
+	{"daily UTC", "*-*-* 00:00:00 UTC"},
+	{"monthly", "*-*-01 00:00:00"},
+	{"weekly", "Mon *-*-* 00:00:00"},
+	{"weekly Pacific/Auckland", "Mon *-*-* 00:00:00 Pacific/Auckland"},
+	{"yearly", "*-01-01 00:00:00"},
+	{"annually", "*-01-01 00:00:00"},
+	{"*:2/3", "*-*-* *:02/3:00"},
+	{"*-*-*/2", "*-*-01/2 00:00:00"},
+	{"*-*/2-01", "*-01/2-01 00:00:00"},
+}
+
+func TestParseNormalized(t *testing.T) {
+	for _, tt := range normalized {
+		spec, err := Parse(tt.expr)
+		if err != nil {
+			if _, tzErr := time.LoadLocation("Pacific/Auckland"); tzErr != nil && tt.expr == "weekly Pacific/Auckland" {
+				continue
+			}
+			t.Errorf("Parse(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+		if got := spec.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"Fri..Mon",
+		"Someday",
+		"*-13-01",
+		"*-*-32",
+		"25:00",
+		"12:60",
+		"*:*/0",
+		"1-2-3-4",
+		"12:00 Not/AZone",
+		"12:00 13:00",
+	} {
+		if _, err := Parse(expr); !errors.Is(err, ErrSyntax) {
+			t.Errorf("Parse(%q) error is %v, but should have been %v", expr, err, ErrSyntax)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// Wednesday.
+	now := time.Date(2024, 2, 28, 10, 15, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want []time.Time
+	}{
+		{"hourly UTC", []time.Time{
+			time.Date(2024, 2, 28, 11, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 28, 12, 0, 0, 0, time.UTC),
+		}},
+		{"daily UTC", []time.Time{
+			time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		{"weekly UTC", []time.Time{
+			time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+		}},
+		{"quarterly UTC", []time.Time{
+			time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		{"Mon..Fri 09:00 UTC", []time.Time{
+			time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC),
+		}},
+		{"*-*-* *:*:* UTC", []time.Time{
+			time.Date(2024, 2, 28, 10, 15, 31, 0, time.UTC),
+			time.Date(2024, 2, 28, 10, 15, 32, 0, time.UTC),
+		}},
+		{"*:*:0/0.5 UTC", []time.Time{
+			time.Date(2024, 2, 28, 10, 15, 30, 500000000, time.UTC),
+			time.Date(2024, 2, 28, 10, 15, 31, 0, time.UTC),
+		}},
+		{"*:0/20 UTC", []time.Time{
+			time.Date(2024, 2, 28, 10, 20, 0, 0, time.UTC),
+			time.Date(2024, 2, 28, 10, 40, 0, 0, time.UTC),
+			time.Date(2024, 2, 28, 11, 0, 0, 0, time.UTC),
+		}},
+		{"*-*-*/2 UTC", []time.Time{
+			time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+		}},
+		{"*-*/2-01 UTC", []time.Time{
+			time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		{"*-02-29 UTC", []time.Time{
+			time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		}},
+		// The last day of every month.
+		{"*-*~01 UTC", []time.Time{
+			time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC),
+		}},
+		// The last Monday in May, from systemd.time(7).
+		{"Mon *-05~07/1 UTC", []time.Time{
+			time.Date(2024, 5, 27, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC),
+		}},
+		{"10:15:30 UTC", []time.Time{
+			time.Date(2024, 2, 29, 10, 15, 30, 0, time.UTC),
+		}},
+		{"2003-03-05 UTC", nil},
+	}
+	for _, tt := range tests {
+		spec, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+		got := spec.NextN(now, len(tt.want)+1)
+		if len(tt.want) > 0 {
+			got = got[:len(tt.want)]
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q: NextN = %v, want %v", tt.expr, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("%q: NextN[%d] = %v, want %v", tt.expr, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestNextTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	spec, err := Parse("daily Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	got := spec.Next(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	want := time.Date(2024, 6, 2, 0, 0, 0, 0, loc)
+	if !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+}
+
+func TestNextDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	// 01:00 EST on 2024-11-03, the second time the clock shows 01:00.
+	fallBack := time.Date(2024, 11, 3, 6, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  []time.Time
+	}{
+		{
+			// 02:30 does not exist on 2024-03-10.
+			name:  "spring forward",
+			expr:  "02:30 America/New_York",
+			after: time.Date(2024, 3, 9, 12, 0, 0, 0, loc),
+			want: []time.Time{
+				time.Date(2024, 3, 11, 2, 30, 0, 0, loc),
+				time.Date(2024, 3, 12, 2, 30, 0, 0, loc),
+			},
+		},
+		{
+			// 01:30 occurs twice on 2024-11-03.
+			name:  "fall back",
+			expr:  "*:30 America/New_York",
+			after: time.Date(2024, 11, 3, 0, 0, 0, 0, loc),
+			want: []time.Time{
+				time.Date(2024, 11, 3, 0, 30, 0, 0, loc),
+				time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC),
+				time.Date(2024, 11, 3, 6, 30, 0, 0, time.UTC),
+				time.Date(2024, 11, 3, 2, 30, 0, 0, loc),
+			},
+		},
+		{
+			name:  "fall back second pass",
+			expr:  "*:30 America/New_York",
+			after: fallBack.Add(15 * time.Minute),
+			want: []time.Time{
+				time.Date(2024, 11, 3, 6, 30, 0, 0, time.UTC),
+				time.Date(2024, 11, 3, 2, 30, 0, 0, loc),
+			},
+		},
+	}
+	for _, tt := range tests {
+		spec, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+		got := spec.NextN(tt.after, len(tt.want))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: NextN = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("%s: NextN[%d] = %v, want %v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
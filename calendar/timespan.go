@@ -0,0 +1,92 @@
+package calendar
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Infinity is returned by ParseTimespan for "infinity".
+const Infinity = time.Duration(math.MaxInt64)
+
+// timespanUnits lists the units accepted in time spans. A month is 30.44
+// days and a year 365.25 days, as defined by systemd.time(7).
+var timespanUnits = map[string]time.Duration{
+	"usec":    time.Microsecond,
+	"us":      time.Microsecond,
+	"µs":      time.Microsecond,
+	"msec":    time.Millisecond,
+	"ms":      time.Millisecond,
+	"seconds": time.Second,
+	"second":  time.Second,
+	"sec":     time.Second,
+	"s":       time.Second,
+	"minutes": time.Minute,
+	"minute":  time.Minute,
+	"min":     time.Minute,
+	"m":       time.Minute,
+	"hours":   time.Hour,
+	"hour":    time.Hour,
+	"hr":      time.Hour,
+	"h":       time.Hour,
+	"days":    24 * time.Hour,
+	"day":     24 * time.Hour,
+	"d":       24 * time.Hour,
+	"weeks":   7 * 24 * time.Hour,
+	"week":    7 * 24 * time.Hour,
+	"w":       7 * 24 * time.Hour,
+	"months":  2629800 * time.Second,
+	"month":   2629800 * time.Second,
+	"M":       2629800 * time.Second,
+	"years":   31557600 * time.Second,
+	"year":    31557600 * time.Second,
+	"y":       31557600 * time.Second,
+}
+
+// ParseTimespan parses a systemd time span such as "5min 20s", "1h30m" or
+// "2.5s". A number without a unit is taken as seconds.
+func ParseTimespan(text string) (time.Duration, error) {
+	s := strings.TrimSpace(text)
+	if s == "infinity" {
+		return Infinity, nil
+	}
+	if s == "" {
+		return 0, fmt.Errorf("empty time span: %w", ErrSyntax)
+	}
+	var total time.Duration
+	for s != "" {
+		end := strings.IndexFunc(s, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+		if end < 0 {
+			end = len(s)
+		}
+		number := s[:end]
+		if number == "" {
+			return 0, fmt.Errorf("invalid time span %q: %w", text, ErrSyntax)
+		}
+		value, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time span %q: %w", text, ErrSyntax)
+		}
+		s = strings.TrimLeft(s[end:], " \t")
+		unitEnd := strings.IndexFunc(s, func(r rune) bool {
+			return (r >= '0' && r <= '9') || r == '.' || r == ' ' || r == '\t'
+		})
+		if unitEnd < 0 {
+			unitEnd = len(s)
+		}
+		unit := time.Second
+		if name := s[:unitEnd]; name != "" {
+			var ok bool
+			if unit, ok = timespanUnits[name]; !ok {
+				return 0, fmt.Errorf("unknown time span unit %q: %w", name, ErrSyntax)
+			}
+		}
+		total += time.Duration(math.Round(value * float64(unit)))
+		s = strings.TrimLeft(s[unitEnd:], " \t")
+	}
+	return total, nil
+}
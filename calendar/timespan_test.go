@@ -0,0 +1,46 @@
+package calendar
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseTimespan(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		// Examples from the "Parsing Time Spans" section of systemd.time(7).
+		{"2 h", 2 * time.Hour},
+		{"2hours", 2 * time.Hour},
+		{"48hr", 48 * time.Hour},
+		{"1y 12month", 2 * 31557600 * time.Second},
+		{"55s500ms", 55500 * time.Millisecond},
+		{"300ms20s 5day", 5*24*time.Hour + 20300*time.Millisecond},
+
+		{"5min 20s", 5*time.Minute + 20*time.Second},
+		{"1h30m", 90 * time.Minute},
+		{"90", 90 * time.Second},
+		{"2.5s", 2500 * time.Millisecond},
+		{"1w", 7 * 24 * time.Hour},
+		{"10us", 10 * time.Microsecond},
+		{"infinity", Infinity},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimespan(tt.value)
+		if err != nil {
+			t.Errorf("ParseTimespan(%q) returned error: %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTimespan(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "5 fortnights", "min", "1..2s"} {
+		if _, err := ParseTimespan(value); !errors.Is(err, ErrSyntax) {
+			t.Errorf("ParseTimespan(%q) error is %v, but should have been %v", value, err, ErrSyntax)
+		}
+	}
+}
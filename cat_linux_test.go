@@ -0,0 +1,42 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadUnit(t *testing.T) {
+	logFile := fakeSystemctl(t, `cat <<'EOF'
+# /lib/systemd/system/nginx.service
+[Service]
+ExecStart=/usr/sbin/nginx
+
+# /etc/systemd/system/nginx.service.d/override.conf
+[Service]
+Restart=always
+EOF`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	files, err := ReadUnit(ctx, "nginx.service", Options{})
+	if err != nil {
+		t.Fatalf("ReadUnit returned error: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("ReadUnit returned %d files, want 2", len(files))
+	}
+	if files[1].Path != "/etc/systemd/system/nginx.service.d/override.conf" {
+		t.Errorf("drop-in path = %q", files[1].Path)
+	}
+	if v, _ := files[1].Value("Service", "Restart"); v != "always" {
+		t.Errorf("Restart = %q, want %q", v, "always")
+	}
+	wantArgs := [][]string{{"cat", "--system", "nginx.service"}}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
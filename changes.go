@@ -0,0 +1,79 @@
+package systemctl
+
+import "strings"
+
+// Symlink describes a symlink created by systemctl while changing the
+// enablement state of a unit file.
+type Symlink struct {
+	// Path is the location of the symlink itself.
+	Path string
+	// Target is the file the symlink points to.
+	Target string
+}
+
+// ChangeSet lists the symlinks created and removed by a systemctl command
+// such as enable, disable, mask or preset.
+//
+// Functions which return a ChangeSet drop a --quiet or -q argument, as it
+// would suppress the output the ChangeSet is built from.
+type ChangeSet struct {
+	Created []Symlink
+	Removed []string
+	// Changed is true if at least one symlink was created or removed.
+	Changed bool
+}
+
+// parseChangeSet extracts the "Created symlink A → B." and "Removed B."
+// lines systemctl prints for each change it makes. Depending on the systemd
+// version these are printed on stdout or stderr, and paths may be quoted, so
+// both streams should be passed in.
+func parseChangeSet(output ...string) ChangeSet {
+	changes := ChangeSet{}
+	for _, out := range output {
+		for _, line := range strings.Split(out, "\n") {
+			line = strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(line, "Created symlink "):
+				rest := strings.TrimSuffix(strings.TrimPrefix(line, "Created symlink "), ".")
+				path, target, ok := strings.Cut(rest, " → ")
+				if !ok {
+					path, target, ok = strings.Cut(rest, " -> ")
+				}
+				if !ok {
+					continue
+				}
+				changes.Created = append(changes.Created, Symlink{
+					Path:   unquotePath(path),
+					Target: unquotePath(target),
+				})
+			case strings.HasPrefix(line, "Removed "):
+				rest := strings.TrimSuffix(strings.TrimPrefix(line, "Removed "), ".")
+				changes.Removed = append(changes.Removed, unquotePath(rest))
+			}
+		}
+	}
+	changes.Changed = len(changes.Created) > 0 || len(changes.Removed) > 0
+	return changes
+}
+
+func unquotePath(path string) string {
+	if len(path) >= 2 {
+		first, last := path[0], path[len(path)-1]
+		if first == last && (first == '"' || first == '\'') {
+			return path[1 : len(path)-1]
+		}
+	}
+	return path
+}
+
+// withoutQuiet removes the flags which suppress systemctl's change report.
+func withoutQuiet(args []string) []string {
+	filtered := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "--quiet" || arg == "-q" {
+			continue
+		}
+		filtered = append(filtered, arg)
+	}
+	return filtered
+}
@@ -0,0 +1,131 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithChanges(t *testing.T) {
+	created := "Created symlink /etc/systemd/system/multi-user.target.wants/nginx.service → /lib/systemd/system/nginx.service."
+	removed := "Removed /etc/systemd/system/multi-user.target.wants/nginx.service."
+	createdSet := ChangeSet{
+		Created: []Symlink{{
+			Path:   "/etc/systemd/system/multi-user.target.wants/nginx.service",
+			Target: "/lib/systemd/system/nginx.service",
+		}},
+		Changed: true,
+	}
+	removedSet := ChangeSet{
+		Removed: []string{"/etc/systemd/system/multi-user.target.wants/nginx.service"},
+		Changed: true,
+	}
+	preset := func(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error) {
+		return Preset(ctx, unit, "", opts, args...)
+	}
+	tests := []struct {
+		name    string
+		fn      func(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error)
+		output  string
+		args    []string
+		want    []string
+		changes ChangeSet
+	}{
+		{name: "enable", fn: EnableWithChanges, output: created, want: []string{"enable", "--system", "nginx.service"}, changes: createdSet},
+		{name: "enable quiet", fn: EnableWithChanges, output: created, args: []string{"--quiet"}, want: []string{"enable", "--system", "nginx.service"}, changes: createdSet},
+		{name: "disable", fn: DisableWithChanges, output: removed, args: []string{"-q", "--now"}, want: []string{"disable", "--system", "nginx.service", "--now"}, changes: removedSet},
+		{name: "mask", fn: MaskWithChanges, output: created, want: []string{"mask", "--system", "nginx.service"}, changes: createdSet},
+		{name: "unmask", fn: UnmaskWithChanges, output: removed, want: []string{"unmask", "--system", "nginx.service"}, changes: removedSet},
+		{name: "preset quiet", fn: preset, output: removed, args: []string{"--quiet"}, want: []string{"preset", "--system", "nginx.service"}, changes: removedSet},
+		{
+			name:   "reenable",
+			fn:     ReenableWithChanges,
+			output: removed + "\n" + created,
+			want:   []string{"reenable", "--system", "nginx.service"},
+			changes: ChangeSet{
+				Created: createdSet.Created,
+				Removed: removedSet.Removed,
+				Changed: true,
+			},
+		},
+		{name: "already enabled", fn: EnableWithChanges, output: "", want: []string{"enable", "--system", "nginx.service"}, changes: ChangeSet{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logFile := fakeSystemctl(t, "printf '%s\\n' '"+tt.output+"' >&2")
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			changes, err := tt.fn(ctx, "nginx.service", Options{}, tt.args...)
+			if err != nil {
+				t.Fatalf("%s returned error: %v", tt.name, err)
+			}
+			if !reflect.DeepEqual(changes, tt.changes) {
+				t.Errorf("changes = %+v, want %+v", changes, tt.changes)
+			}
+			if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, [][]string{tt.want}) {
+				t.Errorf("invocations = %v, want %v", got, [][]string{tt.want})
+			}
+		})
+	}
+}
+
+func TestInstallVerbsBuildExpectedCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(ctx context.Context) (ChangeSet, error)
+		want []string
+	}{
+		{
+			name: "link",
+			fn: func(ctx context.Context) (ChangeSet, error) {
+				return Link(ctx, "/opt/app/app.service", Options{}, "-q")
+			},
+			want: []string{"link", "--system", "/opt/app/app.service"},
+		},
+		{
+			name: "revert",
+			fn: func(ctx context.Context) (ChangeSet, error) {
+				return Revert(ctx, "nginx.service", Options{UserMode: true}, "--quiet")
+			},
+			want: []string{"revert", "--user", "nginx.service"},
+		},
+		{
+			name: "add-wants",
+			fn: func(ctx context.Context) (ChangeSet, error) {
+				return AddWants(ctx, "multi-user", "nginx.service", Options{}, "--quiet")
+			},
+			want: []string{"add-wants", "--system", "multi-user.target", "nginx.service"},
+		},
+		{
+			name: "add-requires",
+			fn: func(ctx context.Context) (ChangeSet, error) {
+				return AddRequires(ctx, "graphical.target", "nginx.service", Options{}, "-q")
+			},
+			want: []string{"add-requires", "--system", "graphical.target", "nginx.service"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logFile := fakeSystemctl(t, `echo "Created symlink /etc/systemd/system/x → /y." >&2`)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			changes, err := tt.fn(ctx)
+			if err != nil {
+				t.Fatalf("%s returned error: %v", tt.name, err)
+			}
+			if !changes.Changed {
+				t.Errorf("%s reported no changes", tt.name)
+			}
+			if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, [][]string{tt.want}) {
+				t.Errorf("invocations = %v, want %v", got, [][]string{tt.want})
+			}
+		})
+	}
+}
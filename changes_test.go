@@ -0,0 +1,74 @@
+package systemctl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseChangeSet(t *testing.T) {
+	tests := []struct {
+		name   string
+		stdout string
+		stderr string
+		want   ChangeSet
+	}{
+		{
+			name: "no output",
+			want: ChangeSet{},
+		},
+		{
+			name:   "enable on older systemd",
+			stderr: "Created symlink /etc/systemd/system/multi-user.target.wants/nginx.service → /lib/systemd/system/nginx.service.\n",
+			want: ChangeSet{
+				Created: []Symlink{{
+					Path:   "/etc/systemd/system/multi-user.target.wants/nginx.service",
+					Target: "/lib/systemd/system/nginx.service",
+				}},
+				Changed: true,
+			},
+		},
+		{
+			name:   "quoted paths and ascii arrow",
+			stderr: "Created symlink '/etc/systemd/system/foo.service' -> '/dev/null'.\n",
+			want: ChangeSet{
+				Created: []Symlink{{Path: "/etc/systemd/system/foo.service", Target: "/dev/null"}},
+				Changed: true,
+			},
+		},
+		{
+			name:   "disable with quoted removal",
+			stdout: "Removed \"/etc/systemd/system/multi-user.target.wants/nginx.service\".\n",
+			want: ChangeSet{
+				Removed: []string{"/etc/systemd/system/multi-user.target.wants/nginx.service"},
+				Changed: true,
+			},
+		},
+		{
+			name: "reenable removes then creates",
+			stderr: "Removed /etc/systemd/system/multi-user.target.wants/nginx.service.\n" +
+				"Created symlink /etc/systemd/system/multi-user.target.wants/nginx.service → /lib/systemd/system/nginx.service.\n",
+			want: ChangeSet{
+				Created: []Symlink{{
+					Path:   "/etc/systemd/system/multi-user.target.wants/nginx.service",
+					Target: "/lib/systemd/system/nginx.service",
+				}},
+				Removed: []string{"/etc/systemd/system/multi-user.target.wants/nginx.service"},
+				Changed: true,
+			},
+		},
+		{
+			name:   "unrelated warnings",
+			stderr: "Synchronizing state of nginx.service with SysV service script with /lib/systemd/systemd-sysv-install.\nExecuting: /lib/systemd/systemd-sysv-install enable nginx\n",
+			want:   ChangeSet{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseChangeSet(tt.stdout, tt.stderr)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseChangeSet() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package systemctl
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/taigrr/systemctl/properties"
+)
+
+// DependencyOptions selects which dependencies ListDependencies reports.
+type DependencyOptions struct {
+	// Reverse shows the units which depend on the unit (WantedBy=,
+	// RequiredBy=, PartOf=, BoundBy=) instead of the units it depends on.
+	Reverse bool
+	// After shows the units ordered before the unit (After=) instead of
+	// requirement dependencies.
+	After bool
+	// Before shows the units ordered after the unit (Before=) instead of
+	// requirement dependencies.
+	Before bool
+	// All expands every unit recursively, not just targets.
+	All bool
+}
+
+func (d DependencyOptions) args() []string {
+	var args []string
+	if d.Reverse {
+		args = append(args, "--reverse")
+	}
+	if d.After {
+		args = append(args, "--after")
+	}
+	if d.Before {
+		args = append(args, "--before")
+	}
+	if d.All {
+		args = append(args, "--all")
+	}
+	return args
+}
+
+// Dependency is a node of the tree printed by `systemctl list-dependencies`.
+type Dependency struct {
+	Unit         string
+	Dependencies []*Dependency
+}
+
+// Units returns the names of all units below d, each listed once, in the
+// order they first appear in the tree.
+func (d *Dependency) Units() []string {
+	seen := map[string]bool{d.Unit: true}
+	units := []string{}
+	var walk func(*Dependency)
+	walk = func(node *Dependency) {
+		for _, dep := range node.Dependencies {
+			if !seen[dep.Unit] {
+				seen[dep.Unit] = true
+				units = append(units, dep.Unit)
+			}
+			walk(dep)
+		}
+	}
+	walk(d)
+	return units
+}
+
+// parseDependencyTree parses the output of `systemctl list-dependencies`.
+// Every level of the tree is indented by two columns of box drawing
+// characters, and newer versions of systemd prefix each dependency with
+// a state bullet.
+func parseDependencyTree(stdout string) (*Dependency, error) {
+	var root *Dependency
+	// stack[i] is the most recent node at depth i.
+	var stack []*Dependency
+	for _, line := range strings.Split(stdout, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		runes := []rune(line)
+		branch := -1
+		for i := 0; i+1 < len(runes); i++ {
+			if (runes[i] == '├' || runes[i] == '└') && runes[i+1] == '─' {
+				branch = i
+				break
+			}
+		}
+		if branch < 0 {
+			if root != nil {
+				return nil, ErrUnspecified
+			}
+			root = &Dependency{Unit: strings.TrimSpace(line)}
+			stack = []*Dependency{root}
+			continue
+		}
+		if root == nil {
+			return nil, ErrUnspecified
+		}
+		// Skip the state bullet and its trailing space, if present.
+		indent := branch
+		if len(runes) > 1 && runes[0] != ' ' && runes[0] != '│' && runes[0] != '├' && runes[0] != '└' {
+			indent -= 2
+		}
+		depth := indent/2 + 1
+		if depth > len(stack) {
+			return nil, ErrUnspecified
+		}
+		node := &Dependency{Unit: strings.TrimSpace(string(runes[branch+2:]))}
+		parent := stack[depth-1]
+		parent.Dependencies = append(parent.Dependencies, node)
+		stack = append(stack[:depth], node)
+	}
+	if root == nil {
+		return nil, ErrUnspecified
+	}
+	return root, nil
+}
+
+// UnitDependencies holds the dependency properties of a single unit.
+type UnitDependencies struct {
+	Requires  []string
+	Wants     []string
+	BindsTo   []string
+	PartOf    []string
+	Conflicts []string
+	After     []string
+	Before    []string
+}
+
+// DependencyGraph maps unit names to their dependency properties.
+type DependencyGraph map[string]UnitDependencies
+
+var dependencyProperties = []properties.Property{
+	properties.Requires,
+	properties.Wants,
+	properties.BindsTo,
+	properties.PartOf,
+	properties.Conflicts,
+	properties.After,
+	properties.Before,
+}
+
+// GetDependencyGraph reads the Requires, Wants, BindsTo, PartOf,
+// Conflicts, After and Before properties of the given units and,
+// transitively, of every unit they require, want, bind to or are part of.
+//
+// Conflicts and ordering dependencies (After and Before) are recorded but
+// not followed, as that would pull in most of the system.
+//
+// The graph is keyed by full unit names, as dependencies are always listed
+// with their type suffix. Names given without a suffix are taken to be
+// services, so "nginx" becomes "nginx.service".
+func GetDependencyGraph(ctx context.Context, units []string, opts Options) (DependencyGraph, error) {
+	return getDependencyGraph(ctx, units, true, opts)
+}
+
+// getDependencyGraph reads the dependencies of the given units, and of the
+// units they require if follow is set.
+func getDependencyGraph(ctx context.Context, units []string, follow bool, opts Options) (DependencyGraph, error) {
+	graph := DependencyGraph{}
+	level := make([]string, len(units))
+	for i, unit := range units {
+		level[i] = withDefaultType(unit, "service")
+	}
+	// Each level of the graph is read with a single `systemctl show` call.
+	for len(level) > 0 {
+		var batch []string
+		for _, unit := range level {
+			if _, ok := graph[unit]; !ok && !slices.Contains(batch, unit) {
+				batch = append(batch, unit)
+			}
+		}
+		if len(batch) == 0 {
+			break
+		}
+		values, err := ShowUnits(ctx, batch, dependencyProperties, opts.withoutFlags())
+		if err != nil {
+			return graph, err
+		}
+		level = nil
+		for _, unit := range batch {
+			v := values[unit]
+			deps := UnitDependencies{
+				Requires:  strings.Fields(v[properties.Requires]),
+				Wants:     strings.Fields(v[properties.Wants]),
+				BindsTo:   strings.Fields(v[properties.BindsTo]),
+				PartOf:    strings.Fields(v[properties.PartOf]),
+				Conflicts: strings.Fields(v[properties.Conflicts]),
+				After:     strings.Fields(v[properties.After]),
+				Before:    strings.Fields(v[properties.Before]),
+			}
+			graph[unit] = deps
+			if follow {
+				level = slices.Concat(level, deps.Requires, deps.Wants, deps.BindsTo, deps.PartOf)
+			}
+		}
+	}
+	return graph, nil
+}
+
+// parseProperties parses the Key=Value lines printed by `systemctl show`.
+func parseProperties(stdout string) map[properties.Property]string {
+	values := map[properties.Property]string{}
+	for _, line := range strings.Split(stdout, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		values[properties.Property(key)] = value
+	}
+	return values
+}
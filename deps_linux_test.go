@@ -0,0 +1,118 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListDependencies(t *testing.T) {
+	logFile := fakeSystemctl(t, `printf 'nginx.service\n● ├─multi-user.target\n● │ └─graphical.target\n● └─remote-fs.target\n'`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tree, err := ListDependencies(ctx, "nginx.service", DependencyOptions{Reverse: true, All: true}, Options{})
+	if err != nil {
+		t.Fatalf("ListDependencies returned error: %v", err)
+	}
+	want := []string{"multi-user.target", "graphical.target", "remote-fs.target"}
+	if got := tree.Units(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Units = %v, want %v", got, want)
+	}
+	wantArgs := [][]string{{"list-dependencies", "--system", "nginx.service", "--no-pager", "--full", "--reverse", "--all"}}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
+
+func TestGetDependencyGraph(t *testing.T) {
+	logFile := fakeSystemctl(t, `for unit in "$@"; do
+	case "$unit" in
+	app.service)
+		printf 'Id=app.service\nRequires=db.service\nWants=cache.service\nBindsTo=\nPartOf=\nConflicts=\nAfter=db.service network.target\nBefore=\n\n' ;;
+	db.service)
+		printf 'Id=db.service\nRequires=\nWants=\nBindsTo=\nPartOf=\nConflicts=\nAfter=network.target\nBefore=app.service\n\n' ;;
+	cache.service)
+		printf 'Id=cache.service\nRequires=\nWants=\nBindsTo=\nPartOf=app.service\nConflicts=\nAfter=\nBefore=\n\n' ;;
+	esac
+done`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	graph, err := GetDependencyGraph(ctx, []string{"app.service"}, Options{})
+	if err != nil {
+		t.Fatalf("GetDependencyGraph returned error: %v", err)
+	}
+	want := DependencyGraph{
+		"app.service": {
+			Requires:  []string{"db.service"},
+			Wants:     []string{"cache.service"},
+			BindsTo:   []string{},
+			PartOf:    []string{},
+			Conflicts: []string{},
+			After:     []string{"db.service", "network.target"},
+			Before:    []string{},
+		},
+		"db.service": {
+			Requires:  []string{},
+			Wants:     []string{},
+			BindsTo:   []string{},
+			PartOf:    []string{},
+			Conflicts: []string{},
+			After:     []string{"network.target"},
+			Before:    []string{"app.service"},
+		},
+		"cache.service": {
+			Requires:  []string{},
+			Wants:     []string{},
+			BindsTo:   []string{},
+			PartOf:    []string{"app.service"},
+			Conflicts: []string{},
+			After:     []string{},
+			Before:    []string{},
+		},
+	}
+	if !reflect.DeepEqual(graph, want) {
+		t.Errorf("GetDependencyGraph = %+v, want %+v", graph, want)
+	}
+	wantArgs := [][]string{
+		{"show", "--system", "app.service", "--property=Id,Requires,Wants,BindsTo,PartOf,Conflicts,After,Before"},
+		{"show", "--system", "db.service", "cache.service", "--property=Id,Requires,Wants,BindsTo,PartOf,Conflicts,After,Before"},
+	}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
+
+func TestGetDependencyGraphBareNames(t *testing.T) {
+	logFile := fakeSystemctl(t, `for unit in "$@"; do
+	case "$unit" in
+	app|app.service)
+		printf 'Id=app.service\nRequires=db.service\nPartOf=\nAfter=db.service\n\n' ;;
+	db|db.service)
+		printf 'Id=db.service\nRequires=\nPartOf=app.service\nAfter=\n\n' ;;
+	esac
+done`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	graph, err := GetDependencyGraph(ctx, []string{"app", "db"}, Options{})
+	if err != nil {
+		t.Fatalf("GetDependencyGraph returned error: %v", err)
+	}
+	if got, want := graph.Units(), []string{"app.service", "db.service"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Units = %v, want %v", got, want)
+	}
+	if cycles := graph.Cycles(); len(cycles) != 0 {
+		t.Errorf("Cycles = %v, want none", cycles)
+	}
+	wantArgs := [][]string{
+		{"show", "--system", "app.service", "db.service", "--property=Id,Requires,Wants,BindsTo,PartOf,Conflicts,After,Before"},
+	}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
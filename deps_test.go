@@ -0,0 +1,80 @@
+package systemctl
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/taigrr/systemctl/properties"
+)
+
+func TestParseDependencyTree(t *testing.T) {
+	stdout := `nginx.service
+● ├─system.slice
+○ ├─-.mount
+● └─sysinit.target
+●   ├─dev-hugepages.mount
+○   ├─cryptsetup.target
+●   │ └─systemd-cryptsetup@root.service
+●   └─swap.target
+`
+	got, err := parseDependencyTree(stdout)
+	if err != nil {
+		t.Fatalf("parseDependencyTree returned error: %v", err)
+	}
+	want := &Dependency{Unit: "nginx.service", Dependencies: []*Dependency{
+		{Unit: "system.slice"},
+		{Unit: "-.mount"},
+		{Unit: "sysinit.target", Dependencies: []*Dependency{
+			{Unit: "dev-hugepages.mount"},
+			{Unit: "cryptsetup.target", Dependencies: []*Dependency{
+				{Unit: "systemd-cryptsetup@root.service"},
+			}},
+			{Unit: "swap.target"},
+		}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDependencyTree = %+v, want %+v", got, want)
+	}
+
+	wantUnits := []string{"system.slice", "-.mount", "sysinit.target", "dev-hugepages.mount", "cryptsetup.target", "systemd-cryptsetup@root.service", "swap.target"}
+	if units := got.Units(); !reflect.DeepEqual(units, wantUnits) {
+		t.Errorf("Units = %v, want %v", units, wantUnits)
+	}
+}
+
+func TestParseDependencyTreeWithoutBullets(t *testing.T) {
+	stdout := "default.target\n└─multi-user.target\n  ├─cron.service\n  └─basic.target\n    └─sockets.target\n"
+	got, err := parseDependencyTree(stdout)
+	if err != nil {
+		t.Fatalf("parseDependencyTree returned error: %v", err)
+	}
+	wantUnits := []string{"multi-user.target", "cron.service", "basic.target", "sockets.target"}
+	if units := got.Units(); !reflect.DeepEqual(units, wantUnits) {
+		t.Errorf("Units = %v, want %v", units, wantUnits)
+	}
+	basic := got.Dependencies[0].Dependencies[1]
+	if basic.Unit != "basic.target" || len(basic.Dependencies) != 1 || basic.Dependencies[0].Unit != "sockets.target" {
+		t.Errorf("basic.target node = %+v", basic)
+	}
+}
+
+func TestParseDependencyTreeInvalid(t *testing.T) {
+	for _, stdout := range []string{"", "├─orphan.service\n", "a.target\nb.target\n", "a.target\n      └─too-deep.service\n"} {
+		if _, err := parseDependencyTree(stdout); !errors.Is(err, ErrUnspecified) {
+			t.Errorf("parseDependencyTree(%q) error is %v, but should have been %v", stdout, err, ErrUnspecified)
+		}
+	}
+}
+
+func TestParseProperties(t *testing.T) {
+	got := parseProperties("Requires=a.service b.socket\nWants=\nAfter=c.target\n")
+	want := map[properties.Property]string{
+		properties.Requires: "a.service b.socket",
+		properties.Wants:    "",
+		properties.After:    "c.target",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseProperties = %v, want %v", got, want)
+	}
+}
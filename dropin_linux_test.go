@@ -0,0 +1,29 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWriteDropInReloads(t *testing.T) {
+	useTempUnitDirs(t)
+	logFile := fakeSystemctl(t, `if [ "$1" = show ]; then echo "DropInPaths=/etc/systemd/system/nginx.service.d/override.conf"; fi`)
+
+	paths, err := WriteDropIn(t.Context(), "nginx", "override", "[Service]\n", Options{}, DropInOptions{Reload: true})
+	if err != nil {
+		t.Fatalf("WriteDropIn returned error: %v", err)
+	}
+	want := []string{"/etc/systemd/system/nginx.service.d/override.conf"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("WriteDropIn paths = %v, want %v", paths, want)
+	}
+	wantArgs := [][]string{
+		{"daemon-reload", "--system"},
+		{"show", "--system", "nginx.service", "--property", "DropInPaths"},
+	}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
@@ -0,0 +1,122 @@
+package systemctl
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func useTempUnitDirs(t *testing.T) string {
+	t.Helper()
+	tempDir := t.TempDir()
+	origSystem, origSystemRuntime := systemConfigDir, systemRuntimeDir
+	origGlobal, origGlobalRuntime := globalConfigDir, globalRuntimeDir
+	systemConfigDir = filepath.Join(tempDir, "etc", "systemd", "system")
+	systemRuntimeDir = filepath.Join(tempDir, "run", "systemd", "system")
+	globalConfigDir = filepath.Join(tempDir, "etc", "systemd", "user")
+	globalRuntimeDir = filepath.Join(tempDir, "run", "systemd", "user")
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+	t.Cleanup(func() {
+		systemConfigDir, systemRuntimeDir = origSystem, origSystemRuntime
+		globalConfigDir, globalRuntimeDir = origGlobal, origGlobalRuntime
+	})
+	return tempDir
+}
+
+func TestDropInDir(t *testing.T) {
+	tempDir := useTempUnitDirs(t)
+	tests := []struct {
+		name  string
+		unit  string
+		opts  Options
+		dopts DropInOptions
+		want  string
+	}{
+		{name: "system", unit: "nginx", want: filepath.Join(tempDir, "etc/systemd/system/nginx.service.d")},
+		{name: "system runtime", unit: "nginx.service", dopts: DropInOptions{Runtime: true}, want: filepath.Join(tempDir, "run/systemd/system/nginx.service.d")},
+		{name: "user", unit: "backup.timer", opts: Options{UserMode: true}, want: filepath.Join(tempDir, "config/systemd/user/backup.timer.d")},
+		{name: "global", unit: "foo", opts: Options{UserMode: true}, dopts: DropInOptions{Global: true}, want: filepath.Join(tempDir, "etc/systemd/user/foo.service.d")},
+		{name: "global runtime", unit: "foo", dopts: DropInOptions{Global: true, Runtime: true}, want: filepath.Join(tempDir, "run/systemd/user/foo.service.d")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dropInDir(tt.unit, tt.opts, tt.dopts)
+			if err != nil {
+				t.Fatalf("dropInDir returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("dropInDir(%q) = %q, want %q", tt.unit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDropInLifecycle(t *testing.T) {
+	tempDir := useTempUnitDirs(t)
+	opts := Options{}
+	dopts := DropInOptions{}
+	dir := filepath.Join(tempDir, "etc/systemd/system/nginx.service.d")
+
+	if _, err := ReadDropIn("nginx", "override", opts, dopts); !errors.Is(err, ErrDoesNotExist) {
+		t.Fatalf("error is %v, but should have been %v", err, ErrDoesNotExist)
+	}
+	dropIns, err := ListDropIns("nginx", opts, dopts)
+	if err != nil || len(dropIns) != 0 {
+		t.Fatalf("ListDropIns = %v, %v, want no drop-ins", dropIns, err)
+	}
+
+	content := "[Service]\nLimitNOFILE=65536\n"
+	paths, err := WriteDropIn(t.Context(), "nginx", "override", content, opts, dopts)
+	if err != nil {
+		t.Fatalf("WriteDropIn returned error: %v", err)
+	}
+	if paths != nil {
+		t.Errorf("WriteDropIn returned paths %v without reloading", paths)
+	}
+	if _, err := WriteDropIn(t.Context(), "nginx", "10-limits.conf", content, opts, dopts); err != nil {
+		t.Fatalf("WriteDropIn returned error: %v", err)
+	}
+	got, err := ReadDropIn("nginx.service", "override.conf", opts, dopts)
+	if err != nil || got != content {
+		t.Fatalf("ReadDropIn = %q, %v, want %q", got, err, content)
+	}
+
+	dropIns, err = ListDropIns("nginx", opts, dopts)
+	if err != nil {
+		t.Fatalf("ListDropIns returned error: %v", err)
+	}
+	want := []DropIn{
+		{Name: "10-limits.conf", Path: filepath.Join(dir, "10-limits.conf")},
+		{Name: "override.conf", Path: filepath.Join(dir, "override.conf")},
+	}
+	if !reflect.DeepEqual(dropIns, want) {
+		t.Errorf("ListDropIns = %v, want %v", dropIns, want)
+	}
+
+	for _, d := range want {
+		if _, err := RemoveDropIn(t.Context(), "nginx", d.Name, opts, dopts); err != nil {
+			t.Fatalf("RemoveDropIn(%q) returned error: %v", d.Name, err)
+		}
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("drop-in directory not removed once empty: %v", err)
+	}
+	if _, err := RemoveDropIn(t.Context(), "nginx", "override", opts, dopts); !errors.Is(err, ErrDoesNotExist) {
+		t.Errorf("error is %v, but should have been %v", err, ErrDoesNotExist)
+	}
+}
+
+func TestDropInRejectsInvalidNames(t *testing.T) {
+	useTempUnitDirs(t)
+	for _, name := range []string{"", "../escape", ".hidden.conf", "a/b.conf"} {
+		if _, err := WriteDropIn(t.Context(), "nginx", name, "", Options{}, DropInOptions{}); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("WriteDropIn(%q) error is %v, but should have been %v", name, err, ErrInvalidName)
+		}
+	}
+	if _, err := ReadDropIn("../nginx", "override", Options{}, DropInOptions{}); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("error is %v, but should have been %v", err, ErrInvalidName)
+	}
+}
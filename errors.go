@@ -12,10 +12,20 @@ var (
 	ErrDoesNotExist = errors.New("unit does not exist")
 	// The provided context was cancelled before the command finished execution
 	ErrExecTimeout = errors.New("command timed out")
+	// An additional argument is not a known systemctl flag while Options.Strict
+	// is set, a Flag in Options.Flags can't be used with the command, or
+	// TransientOptions combines settings systemd-run refuses
+	ErrFlagNotAllowed = errors.New("flag not allowed")
 	// The executable was invoked without enough permissions to run the selected command
 	// Running as superuser or adding the correct PolicyKit definitions can fix this
 	// See https://wiki.debian.org/PolicyKit for more information
 	ErrInsufficientPermissions = errors.New("insufficient permissions")
+	// A unit or file name is empty or contains characters which are not allowed,
+	// or RunTransient was given no command
+	ErrInvalidName = errors.New("invalid name")
+	// The unit refuses to be isolated, either because AllowIsolate= is not set
+	// or because the unit cannot currently be started
+	ErrIsolateNotAllowed = errors.New("unit may not be isolated")
 	// Selected unit file resides outside of the unit file search path
 	ErrLinked = errors.New("unit file linked")
 	// Masked units can only be unmasked, but something else was attempted
@@ -23,12 +33,25 @@ var (
 	ErrMasked = errors.New("unit masked")
 	// Make sure systemctl is in the PATH before calling again
 	ErrNotInstalled = errors.New("systemctl not in $PATH")
+	// The units are ordered after each other (After=/Before=) in a loop
+	// DependencyGraph.Cycles lists the units involved
+	ErrOrderingCycle = errors.New("ordering cycle")
+	// The user named in Options.User has no running user manager
+	// Enable lingering for the user or wait for them to log in
+	ErrUserManagerNotRunning = errors.New("user manager not running")
+	// Make sure loginctl is in the PATH before managing user lingering
+	ErrLoginctlNotInstalled = errors.New("loginctl not in $PATH")
+	// Make sure systemd-run is in the PATH before running transient units
+	ErrSystemdRunNotInstalled = errors.New("systemd-run not in $PATH")
 	// A unit was expected to be running but was found inactive
 	// This can happen when calling GetStartTime on a dead unit, for example
 	ErrUnitNotActive = errors.New("unit not active")
 	// A unit was expected to be loaded, but was not.
 	// This can happen when trying to Stop a unit which does not exist, for example
 	ErrUnitNotLoaded = errors.New("unit not loaded")
+	// The installed systemd is too old for the requested command, flag or property
+	// GetSystemdVersion reports the version found
+	ErrUnsupportedBySystemd = errors.New("unsupported by this systemd version")
 	// An expected value is unavailable, but the unit may be running
 	// This can happen when calling GetMemoryUsage on systemd itself, for example
 	ErrValueNotSet = errors.New("value not set")
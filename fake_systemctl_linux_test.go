@@ -0,0 +1,79 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeSystemctl replaces the systemctl binary with a shell script for the
+// duration of the test. Every invocation appends its arguments, one per line
+// followed by an "@@" separator, to the returned log file. The body is run
+// after logging and may inspect "$@" to choose its output.
+//
+// The systemd version is treated as unknown, so no version check runs; use
+// fakeSystemdVersion to pretend a specific version.
+func fakeSystemctl(t *testing.T, body string) string {
+	t.Helper()
+	fakeSystemdVersion(t, nil)
+	return fakeBinary(t, &systemctl, "systemctl", body)
+}
+
+// fakeSystemdVersion replaces the cached systemd version for the duration
+// of the test. A nil version is treated as unknown.
+func fakeSystemdVersion(t *testing.T, v *SystemdVersion) {
+	t.Helper()
+	if v == nil {
+		v = &SystemdVersion{}
+	}
+	original := version
+	version = v
+	t.Cleanup(func() {
+		version = original
+	})
+}
+
+// fakeBinary works like fakeSystemctl for any of the systemd binaries
+// tracked in util.go, such as systemd-run.
+func fakeBinary(t *testing.T, bin *string, name string, body string) string {
+	t.Helper()
+	tempDir := t.TempDir()
+	logFile := filepath.Join(tempDir, "args.log")
+	fake := filepath.Join(tempDir, name)
+	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" @@ >> '" + logFile + "'\n" + body + "\n"
+	if err := os.WriteFile(fake, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake %s: %v", name, err)
+	}
+	original := *bin
+	*bin = fake
+	t.Cleanup(func() {
+		*bin = original
+	})
+	return logFile
+}
+
+// fakeInvocations returns the argument lists recorded by fakeSystemctl.
+func fakeInvocations(t *testing.T, logFile string) [][]string {
+	t.Helper()
+	b, err := os.ReadFile(logFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		t.Fatalf("read captured args: %v", err)
+	}
+	var calls [][]string
+	var cur []string
+	for _, line := range strings.Split(strings.TrimSuffix(string(b), "\n"), "\n") {
+		if line == "@@" {
+			calls = append(calls, cur)
+			cur = nil
+			continue
+		}
+		cur = append(cur, line)
+	}
+	return calls
+}
@@ -56,6 +56,11 @@ func TestFilterErr(t *testing.T) {
 			stderr: "Unit foo.service is masked.",
 			want:   ErrMasked,
 		},
+		{
+			name:   "isolation refused",
+			stderr: "Failed to start rescue.target: Operation refused, unit may not be isolated.",
+			want:   ErrIsolateNotAllowed,
+		},
 		{
 			name:   "generic failed",
 			stderr: "Failed to do something unknown",
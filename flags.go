@@ -0,0 +1,174 @@
+package systemctl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Flag is a systemctl flag set in Options.Flags. Unlike additional
+// arguments, flags are checked against the command they are used with,
+// and ErrFlagNotAllowed is returned before systemctl is run if the
+// command doesn't support them.
+type Flag struct {
+	name  string
+	value string
+}
+
+// String returns the flag as passed to systemctl, e.g. "--job-mode=fail".
+func (f Flag) String() string {
+	if f.value == "" {
+		return f.name
+	}
+	return f.name + "=" + f.value
+}
+
+// WithNow also starts units when enabling them, and stops them when
+// disabling or masking them (--now).
+func WithNow() Flag {
+	return Flag{name: "--now"}
+}
+
+// WithForce overwrites conflicting symlinks when enabling, linking or
+// presetting units (--force).
+func WithForce() Flag {
+	return Flag{name: "--force"}
+}
+
+// WithRuntime makes unit file changes only until the next reboot, by
+// writing them below /run (--runtime).
+func WithRuntime() Flag {
+	return Flag{name: "--runtime"}
+}
+
+// WithNoBlock returns as soon as the job is queued, instead of waiting
+// for it to finish (--no-block).
+func WithNoBlock() Flag {
+	return Flag{name: "--no-block"}
+}
+
+// WithWait waits until the boot process has completed for
+// IsSystemRunning, until the signalled processes have terminated for Kill,
+// and until the started units have terminated again for Start (--wait).
+func WithWait() Flag {
+	return Flag{name: "--wait"}
+}
+
+// WithJobMode controls how the job for a start, stop, restart or reload
+// deals with already queued jobs (--job-mode=).
+func WithJobMode(mode JobMode) Flag {
+	return Flag{name: "--job-mode", value: string(mode)}
+}
+
+// WithSignal chooses the signal Kill sends, e.g. "SIGHUP" (--signal=).
+func WithSignal(signal string) Flag {
+	return Flag{name: "--signal", value: signal}
+}
+
+// JobMode selects how a new job interacts with queued jobs, see
+// --job-mode= in systemctl(1).
+type JobMode string
+
+const (
+	JobModeFail                JobMode = "fail"
+	JobModeReplace             JobMode = "replace"
+	JobModeReplaceIrreversibly JobMode = "replace-irreversibly"
+	JobModeIsolate             JobMode = "isolate"
+	JobModeIgnoreDependencies  JobMode = "ignore-dependencies"
+	JobModeIgnoreRequirements  JobMode = "ignore-requirements"
+	JobModeFlush               JobMode = "flush"
+	JobModeTriggering          JobMode = "triggering"
+	JobModeRestartDependencies JobMode = "restart-dependencies"
+)
+
+var jobModes = []JobMode{
+	JobModeFail,
+	JobModeReplace,
+	JobModeReplaceIrreversibly,
+	JobModeIsolate,
+	JobModeIgnoreDependencies,
+	JobModeIgnoreRequirements,
+	JobModeFlush,
+	JobModeTriggering,
+	JobModeRestartDependencies,
+}
+
+// flagVerbs lists the commands each flag may be used with.
+var flagVerbs = map[string][]string{
+	"--now":      {"enable", "disable", "mask"},
+	"--force":    {"enable", "reenable", "link", "preset", "preset-all"},
+	"--runtime":  {"enable", "disable", "reenable", "mask", "unmask", "link", "preset", "preset-all", "add-wants", "add-requires"},
+	"--no-block": {"start", "stop", "restart", "reload", "isolate", "emergency", "rescue", "default", "enable", "disable", "mask", "kill"},
+	"--job-mode": {"start", "stop", "restart", "reload"},
+	"--signal":   {"kill"},
+	"--wait":     {"is-system-running", "kill", "start"},
+}
+
+// conflictingFlags lists pairs of flags which can't be combined.
+var conflictingFlags = [][2]string{
+	{"--wait", "--no-block"},
+}
+
+// flagArgs checks flags against the command verb and returns them as
+// systemctl arguments. Each flag may be given only once, and not together
+// with a flag it conflicts with.
+func flagArgs(verb string, flags []Flag) ([]string, error) {
+	var args []string
+	seen := make(map[string]bool, len(flags))
+	for _, f := range flags {
+		verbs, ok := flagVerbs[f.name]
+		if !ok {
+			return nil, fmt.Errorf("unknown flag %q: %w", f.name, ErrFlagNotAllowed)
+		}
+		if !slices.Contains(verbs, verb) {
+			return nil, fmt.Errorf("%s can't be used with %s: %w", f.name, verb, ErrFlagNotAllowed)
+		}
+		if seen[f.name] {
+			return nil, fmt.Errorf("%s given more than once: %w", f.name, ErrFlagNotAllowed)
+		}
+		seen[f.name] = true
+		if err := f.validate(); err != nil {
+			return nil, err
+		}
+		args = append(args, f.String())
+	}
+	for _, pair := range conflictingFlags {
+		if seen[pair[0]] && seen[pair[1]] {
+			return nil, fmt.Errorf("%s can't be combined with %s: %w", pair[0], pair[1], ErrFlagNotAllowed)
+		}
+	}
+	return args, nil
+}
+
+func (f Flag) validate() error {
+	switch f.name {
+	case "--job-mode":
+		if !slices.Contains(jobModes, JobMode(f.value)) {
+			return fmt.Errorf("unknown job mode %q: %w", f.value, ErrFlagNotAllowed)
+		}
+	case "--signal":
+		if f.value == "" || strings.Trim(f.value, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789+-") != "" {
+			return fmt.Errorf("invalid signal %q: %w", f.value, ErrFlagNotAllowed)
+		}
+	}
+	return nil
+}
+
+// withoutFlags returns opts without any Flags, for the queries the library
+// runs on its own behalf in preparation of the command the flags are for.
+func (o Options) withoutFlags() Options {
+	o.Flags = nil
+	return o
+}
+
+// flagsFor returns opts with only the Flags which can be used with verb.
+func (o Options) flagsFor(verb string) Options {
+	var flags []Flag
+	for _, f := range o.Flags {
+		if slices.Contains(flagVerbs[f.name], verb) {
+			flags = append(flags, f)
+		}
+	}
+	o.Flags = flags
+	return o
+}
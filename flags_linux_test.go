@@ -0,0 +1,44 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFlags(t *testing.T) {
+	logFile := fakeSystemctl(t, `case "$1" in
+show) echo "CanIsolate=yes" ;;
+esac`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := Enable(ctx, "nginx", Options{Flags: []Flag{WithNow(), WithRuntime()}}, "--quiet"); err != nil {
+		t.Errorf("Enable returned error: %v", err)
+	}
+	if err := Start(ctx, "nginx", Options{Flags: []Flag{WithNow()}}); !errors.Is(err, ErrFlagNotAllowed) {
+		t.Errorf("Start with --now error is %v, but should have been %v", err, ErrFlagNotAllowed)
+	}
+	if err := Kill(ctx, "nginx", Options{Flags: []Flag{WithSignal("SIGHUP")}}); err != nil {
+		t.Errorf("Kill returned error: %v", err)
+	}
+	// The queries Isolate runs first must not receive the flags.
+	if err := Isolate(ctx, "rescue", Options{Flags: []Flag{WithNoBlock()}}); err != nil {
+		t.Errorf("Isolate returned error: %v", err)
+	}
+
+	wantArgs := [][]string{
+		{"enable", "--system", "nginx", "--now", "--runtime", "--quiet"},
+		{"kill", "--system", "nginx", "--signal=SIGHUP"},
+		{"show", "--system", "rescue.target", "--property", "LoadState"},
+		{"show", "--system", "rescue.target", "--property", "CanIsolate"},
+		{"isolate", "--system", "rescue.target", "--no-block"},
+	}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
@@ -0,0 +1,56 @@
+package systemctl
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFlagArgs(t *testing.T) {
+	tests := []struct {
+		verb  string
+		flags []Flag
+		want  []string
+	}{
+		{"enable", []Flag{WithNow(), WithForce(), WithRuntime()}, []string{"--now", "--force", "--runtime"}},
+		{"disable", []Flag{WithNow(), WithNoBlock()}, []string{"--now", "--no-block"}},
+		{"start", []Flag{WithNoBlock(), WithJobMode(JobModeReplaceIrreversibly)}, []string{"--no-block", "--job-mode=replace-irreversibly"}},
+		{"kill", []Flag{WithSignal("SIGRTMIN+1")}, []string{"--signal=SIGRTMIN+1"}},
+		{"is-system-running", []Flag{WithWait()}, []string{"--wait"}},
+		{"stop", nil, nil},
+	}
+	for _, tt := range tests {
+		got, err := flagArgs(tt.verb, tt.flags)
+		if err != nil {
+			t.Errorf("flagArgs(%s, %v) returned error: %v", tt.verb, tt.flags, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("flagArgs(%s, %v) = %v, want %v", tt.verb, tt.flags, got, tt.want)
+		}
+	}
+
+	invalid := []struct {
+		verb  string
+		flags []Flag
+	}{
+		{"start", []Flag{WithNow()}},
+		{"restart", []Flag{WithForce()}},
+		{"show", []Flag{WithNoBlock()}},
+		{"start", []Flag{WithRuntime()}},
+		{"enable", []Flag{WithJobMode(JobModeFail)}},
+		{"stop", []Flag{WithSignal("SIGKILL")}},
+		{"start", []Flag{WithJobMode("sometimes")}},
+		{"kill", []Flag{WithSignal("")}},
+		{"kill", []Flag{WithSignal("KILL; reboot")}},
+		{"enable", []Flag{WithNow(), WithNow()}},
+		{"start", []Flag{WithWait(), WithNoBlock()}},
+		{"is-system-running", []Flag{WithNoBlock()}},
+		{"enable", []Flag{{}}},
+	}
+	for _, tt := range invalid {
+		if _, err := flagArgs(tt.verb, tt.flags); !errors.Is(err, ErrFlagNotAllowed) {
+			t.Errorf("flagArgs(%s, %v) error is %v, but should have been %v", tt.verb, tt.flags, err, ErrFlagNotAllowed)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package systemctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/taigrr/systemctl/properties"
+)
+
+// Edge is a single dependency between two units. Type is the property the
+// dependency comes from. Before= dependencies are reported as the
+// equivalent After= dependency of the other unit, so an After edge always
+// means From is started after To.
+type Edge struct {
+	From string              `json:"from"`
+	To   string              `json:"to"`
+	Type properties.Property `json:"type"`
+}
+
+// edgeColors follows the colors used by `systemd-analyze dot`.
+var edgeColors = map[properties.Property]string{
+	properties.Requires:  "black",
+	properties.BindsTo:   "black",
+	properties.Wants:     "grey66",
+	properties.PartOf:    "grey66",
+	properties.Conflicts: "red",
+	properties.After:     "green",
+}
+
+// Units returns the units in the graph, sorted by name.
+func (g DependencyGraph) Units() []string {
+	units := make([]string, 0, len(g))
+	for unit := range g {
+		units = append(units, unit)
+	}
+	slices.Sort(units)
+	return units
+}
+
+// Edges returns every dependency of the units in the graph, sorted by
+// unit and type. The units depended upon need not be part of the graph.
+func (g DependencyGraph) Edges() []Edge {
+	seen := map[Edge]bool{}
+	edges := []Edge{}
+	add := func(e Edge) {
+		if !seen[e] {
+			seen[e] = true
+			edges = append(edges, e)
+		}
+	}
+	for _, unit := range g.Units() {
+		deps := g[unit]
+		for _, set := range []struct {
+			typ   properties.Property
+			units []string
+		}{
+			{properties.Requires, deps.Requires},
+			{properties.Wants, deps.Wants},
+			{properties.BindsTo, deps.BindsTo},
+			{properties.PartOf, deps.PartOf},
+			{properties.Conflicts, deps.Conflicts},
+			{properties.After, deps.After},
+		} {
+			for _, to := range set.units {
+				add(Edge{From: unit, To: to, Type: set.typ})
+			}
+		}
+		for _, later := range deps.Before {
+			add(Edge{From: later, To: unit, Type: properties.After})
+		}
+	}
+	slices.SortStableFunc(edges, func(a, b Edge) int {
+		if c := strings.Compare(a.From, b.From); c != 0 {
+			return c
+		}
+		if c := strings.Compare(string(a.Type), string(b.Type)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.To, b.To)
+	})
+	return edges
+}
+
+// DOT renders the graph in the Graphviz DOT language, with edges colored
+// like `systemd-analyze dot`: black for Requires and BindsTo, grey for
+// Wants and PartOf, red for Conflicts and green for ordering.
+func (g DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph systemd {\n")
+	for _, unit := range g.Units() {
+		fmt.Fprintf(&b, "\t%s;\n", dotQuote(unit))
+	}
+	for _, e := range g.Edges() {
+		fmt.Fprintf(&b, "\t%s->%s [color=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(edgeColors[e.Type]))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote quotes an ID for Graphviz. Only '"' and '\' are escaped, so the
+// backslashes of escaped unit names such as "foo\x2dbar.service" survive
+// and nothing is turned into a Go escape Graphviz doesn't understand.
+func dotQuote(id string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(id) + `"`
+}
+
+// JSON renders the graph as an object with a sorted "units" list and the
+// "edges" returned by Edges.
+func (g DependencyGraph) JSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Units []string `json:"units"`
+		Edges []Edge   `json:"edges"`
+	}{g.Units(), g.Edges()})
+}
+
+// orderingEdges returns, for every unit in the graph, the units of the
+// graph which must be started before it. Dependencies on units outside
+// the graph are ignored.
+func (g DependencyGraph) orderingEdges() map[string][]string {
+	before := map[string][]string{}
+	for _, e := range g.Edges() {
+		if e.Type != properties.After {
+			continue
+		}
+		if _, ok := g[e.From]; !ok {
+			continue
+		}
+		if _, ok := g[e.To]; !ok {
+			continue
+		}
+		before[e.From] = append(before[e.From], e.To)
+	}
+	return before
+}
+
+// Cycles returns the groups of units whose ordering dependencies form a
+// loop, each sorted by name. systemd breaks such loops at runtime by
+// dropping jobs, so they are usually configuration mistakes.
+func (g DependencyGraph) Cycles() [][]string {
+	before := g.orderingEdges()
+	// Tarjan's strongly connected components algorithm.
+	index := map[string]int{}
+	lowlink := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var cycles [][]string
+	var visit func(string)
+	visit = func(unit string) {
+		index[unit] = len(index)
+		lowlink[unit] = index[unit]
+		stack = append(stack, unit)
+		onStack[unit] = true
+		for _, dep := range before[unit] {
+			if _, ok := index[dep]; !ok {
+				visit(dep)
+				lowlink[unit] = min(lowlink[unit], lowlink[dep])
+			} else if onStack[dep] {
+				lowlink[unit] = min(lowlink[unit], index[dep])
+			}
+		}
+		if lowlink[unit] != index[unit] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == unit {
+				break
+			}
+		}
+		if len(component) > 1 || slices.Contains(before[unit], unit) {
+			slices.Sort(component)
+			cycles = append(cycles, component)
+		}
+	}
+	for _, unit := range g.Units() {
+		if _, ok := index[unit]; !ok {
+			visit(unit)
+		}
+	}
+	slices.SortFunc(cycles, func(a, b []string) int {
+		return strings.Compare(a[0], b[0])
+	})
+	return cycles
+}
+
+// StartOrder returns the units of the graph in an order in which they can
+// be started one after another without violating their After= and Before=
+// dependencies. Units are grouped by how many units must start before them
+// and sorted by name within a group. ErrOrderingCycle is returned if the
+// ordering dependencies loop.
+func (g DependencyGraph) StartOrder() ([]string, error) {
+	layers, err := g.startLayers()
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(layers...), nil
+}
+
+// StopOrder returns the reverse of StartOrder, which is the order systemd
+// stops units in.
+func (g DependencyGraph) StopOrder() ([]string, error) {
+	order, err := g.StartOrder()
+	slices.Reverse(order)
+	return order, err
+}
+
+// startLayers groups the units of the graph so that every unit is ordered
+// only after units of earlier layers. The units of a layer can be started
+// in parallel.
+func (g DependencyGraph) startLayers() ([][]string, error) {
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		names := make([]string, len(cycles))
+		for i, cycle := range cycles {
+			names[i] = strings.Join(cycle, ", ")
+		}
+		return nil, fmt.Errorf("%s: %w", strings.Join(names, "; "), ErrOrderingCycle)
+	}
+	before := g.orderingEdges()
+	remaining := map[string]int{}
+	after := map[string][]string{}
+	for _, unit := range g.Units() {
+		remaining[unit] = len(before[unit])
+		for _, dep := range before[unit] {
+			after[dep] = append(after[dep], unit)
+		}
+	}
+	var layers [][]string
+	for len(remaining) > 0 {
+		var layer []string
+		for unit, n := range remaining {
+			if n == 0 {
+				layer = append(layer, unit)
+			}
+		}
+		slices.Sort(layer)
+		for _, unit := range layer {
+			delete(remaining, unit)
+			for _, next := range after[unit] {
+				remaining[next]--
+			}
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
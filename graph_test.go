@@ -0,0 +1,144 @@
+package systemctl
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/taigrr/systemctl/properties"
+)
+
+// testGraph is a small web stack: the app needs the database and cache,
+// both of which start after the network.
+var testGraph = DependencyGraph{
+	"app.service": {
+		Requires: []string{"db.service"},
+		Wants:    []string{"cache.service"},
+		After:    []string{"db.service", "cache.service", "network.target"},
+	},
+	"db.service": {
+		After:  []string{"network.target"},
+		Before: []string{"app.service"},
+	},
+	"cache.service": {
+		PartOf:    []string{"app.service"},
+		Conflicts: []string{"memcached.service"},
+		After:     []string{"network.target"},
+	},
+	"network.target": {},
+}
+
+func TestDependencyGraphEdges(t *testing.T) {
+	want := []Edge{
+		{From: "app.service", To: "cache.service", Type: properties.After},
+		{From: "app.service", To: "db.service", Type: properties.After},
+		{From: "app.service", To: "network.target", Type: properties.After},
+		{From: "app.service", To: "db.service", Type: properties.Requires},
+		{From: "app.service", To: "cache.service", Type: properties.Wants},
+		{From: "cache.service", To: "network.target", Type: properties.After},
+		{From: "cache.service", To: "memcached.service", Type: properties.Conflicts},
+		{From: "cache.service", To: "app.service", Type: properties.PartOf},
+		{From: "db.service", To: "network.target", Type: properties.After},
+	}
+	if got := testGraph.Edges(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Edges = %v, want %v", got, want)
+	}
+}
+
+func TestDependencyGraphDOT(t *testing.T) {
+	graph := DependencyGraph{
+		"a.service": {Requires: []string{"b.service"}, After: []string{"b.service"}},
+		"b.service": {Conflicts: []string{"c.service"}},
+	}
+	want := `digraph systemd {
+	"a.service";
+	"b.service";
+	"a.service"->"b.service" [color="green"];
+	"a.service"->"b.service" [color="black"];
+	"b.service"->"c.service" [color="red"];
+}
+`
+	if got := graph.DOT(); got != want {
+		t.Errorf("DOT =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDotQuote(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"a.service", `"a.service"`},
+		{`foo\x2dbar.service`, `"foo\\x2dbar.service"`},
+		{`say"hi".service`, `"say\"hi\".service"`},
+		{"caf\u00e9\x7f.service", "\"caf\u00e9\x7f.service\""},
+	}
+	for _, tt := range tests {
+		if got := dotQuote(tt.id); got != tt.want {
+			t.Errorf("dotQuote(%q) = %s, want %s", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestDependencyGraphJSON(t *testing.T) {
+	graph := DependencyGraph{
+		"a.service": {Wants: []string{"b.service"}},
+	}
+	b, err := graph.JSON()
+	if err != nil {
+		t.Fatalf("JSON returned error: %v", err)
+	}
+	want := `{"units":["a.service"],"edges":[{"from":"a.service","to":"b.service","type":"Wants"}]}`
+	if string(b) != want {
+		t.Errorf("JSON = %s, want %s", b, want)
+	}
+	if !json.Valid(b) {
+		t.Errorf("JSON output is not valid JSON")
+	}
+}
+
+func TestDependencyGraphOrder(t *testing.T) {
+	if cycles := testGraph.Cycles(); len(cycles) != 0 {
+		t.Errorf("Cycles = %v, want none", cycles)
+	}
+	start, err := testGraph.StartOrder()
+	if err != nil {
+		t.Fatalf("StartOrder returned error: %v", err)
+	}
+	wantStart := []string{"network.target", "cache.service", "db.service", "app.service"}
+	if !reflect.DeepEqual(start, wantStart) {
+		t.Errorf("StartOrder = %v, want %v", start, wantStart)
+	}
+	stop, err := testGraph.StopOrder()
+	if err != nil {
+		t.Fatalf("StopOrder returned error: %v", err)
+	}
+	wantStop := []string{"app.service", "db.service", "cache.service", "network.target"}
+	if !reflect.DeepEqual(stop, wantStop) {
+		t.Errorf("StopOrder = %v, want %v", stop, wantStop)
+	}
+}
+
+func TestDependencyGraphCycles(t *testing.T) {
+	graph := DependencyGraph{
+		"a.service": {After: []string{"b.service"}},
+		"b.service": {After: []string{"c.service"}},
+		"c.service": {Before: []string{"a.service"}, After: []string{"a.service"}},
+		"d.service": {After: []string{"d.service"}},
+		"e.service": {After: []string{"a.service", "outside.target"}},
+	}
+	want := [][]string{
+		{"a.service", "b.service", "c.service"},
+		{"d.service"},
+	}
+	if got := graph.Cycles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles = %v, want %v", got, want)
+	}
+	if _, err := graph.StartOrder(); !errors.Is(err, ErrOrderingCycle) {
+		t.Errorf("StartOrder error is %v, but should have been %v", err, ErrOrderingCycle)
+	}
+	if _, err := graph.StopOrder(); !errors.Is(err, ErrOrderingCycle) {
+		t.Errorf("StopOrder error is %v, but should have been %v", err, ErrOrderingCycle)
+	}
+}
@@ -0,0 +1,141 @@
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// GroupOptions controls how StartGroup and StopGroup work through units.
+type GroupOptions struct {
+	// Concurrency limits how many units are started or stopped at the same
+	// time. Zero or less means no limit.
+	Concurrency int
+	// ContinueOnError keeps starting or stopping the remaining units after
+	// one of them failed. By default, units which have not been started or
+	// stopped yet when the first failure happens are skipped.
+	ContinueOnError bool
+}
+
+// GroupResult is the outcome of starting or stopping one unit of a group.
+type GroupResult struct {
+	// Unit is the full unit name, e.g. "app.service" for "app".
+	Unit string
+	// Err is the error returned for the unit, if any.
+	Err error
+	// Skipped is set if the unit was not touched, because another unit
+	// failed first or the context was cancelled.
+	Skipped bool
+}
+
+// StartGroup starts the given units in the order given by their After= and
+// Before= dependencies on each other. Units which are not ordered against
+// each other are started in parallel, up to the concurrency limit in gopts.
+//
+// The results are returned in start order. The error joins the errors of
+// all units which failed to start, each prefixed with the unit name.
+//
+// Names without a type suffix are taken to be services. Ordering
+// dependencies on units outside the group are not taken into account.
+// ErrOrderingCycle is returned if the units are ordered after each other
+// in a loop, before any unit is started.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func StartGroup(ctx context.Context, units []string, gopts GroupOptions, opts Options, args ...string) ([]GroupResult, error) {
+	return runGroup(ctx, units, false, gopts, opts, func(ctx context.Context, unit string) error {
+		return Start(ctx, unit, opts, args...)
+	})
+}
+
+// StopGroup stops the given units in the reverse of the order StartGroup
+// would start them in, so a unit is only stopped once every unit of the
+// group ordered after it has stopped. See StartGroup for details.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func StopGroup(ctx context.Context, units []string, gopts GroupOptions, opts Options, args ...string) ([]GroupResult, error) {
+	return runGroup(ctx, units, true, gopts, opts, func(ctx context.Context, unit string) error {
+		return Stop(ctx, unit, opts, args...)
+	})
+}
+
+func runGroup(ctx context.Context, units []string, reverse bool, gopts GroupOptions, opts Options, action func(context.Context, string) error) ([]GroupResult, error) {
+	graph, err := getDependencyGraph(ctx, units, false, opts.withoutFlags())
+	if err != nil {
+		return nil, err
+	}
+	order, err := graph.StartOrder()
+	if err != nil {
+		return nil, err
+	}
+	// waitFor lists the units which have to be done before a unit.
+	waitFor := graph.orderingEdges()
+	if reverse {
+		slices.Reverse(order)
+		inverted := map[string][]string{}
+		for unit, deps := range waitFor {
+			for _, dep := range deps {
+				inverted[dep] = append(inverted[dep], unit)
+			}
+		}
+		waitFor = inverted
+	}
+
+	limit := gopts.Concurrency
+	if limit <= 0 {
+		limit = len(order)
+	}
+	// pending counts the units each unit is still waiting for, and next
+	// lists the units waiting for it.
+	pending := make(map[string]int, len(order))
+	next := map[string][]string{}
+	for _, unit := range order {
+		pending[unit] = len(waitFor[unit])
+		for _, dep := range waitFor[unit] {
+			next[dep] = append(next[dep], unit)
+		}
+	}
+	results := make([]GroupResult, len(order))
+	finished := make(chan int)
+	started := make([]bool, len(order))
+	running, failed := 0, false
+	for remaining := len(order); remaining > 0; remaining-- {
+		// Launch ready units in order, so a limit of one is deterministic.
+		for i, unit := range order {
+			if running >= limit {
+				break
+			}
+			if started[i] || pending[unit] > 0 {
+				continue
+			}
+			started[i] = true
+			results[i].Unit = unit
+			if (failed && !gopts.ContinueOnError) || ctx.Err() != nil {
+				results[i].Skipped = true
+				go func() { finished <- i }()
+			} else {
+				go func() {
+					results[i].Err = action(ctx, unit)
+					finished <- i
+				}()
+			}
+			running++
+		}
+		i := <-finished
+		running--
+		if results[i].Err != nil {
+			failed = true
+		}
+		for _, unit := range next[order[i]] {
+			pending[unit]--
+		}
+	}
+
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Unit, r.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,159 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// groupSystemctl fakes a stack where app.service is ordered after
+// db.service and cache.service, which are ordered after network.target.
+// Starting or stopping bad.service fails.
+const groupSystemctl = `case "$1" in
+show)
+	for unit in "$@"; do
+		case "$unit" in
+		app.service) printf 'Id=%s\nAfter=db.service cache.service network.target\n\n' "$unit" ;;
+		db.service|cache.service) printf 'Id=%s\nAfter=network.target\n\n' "$unit" ;;
+		bad.service) printf 'Id=%s\nBefore=app.service\n\n' "$unit" ;;
+		-*|show) ;;
+		*) printf 'Id=%s\n\n' "$unit" ;;
+		esac
+	done ;;
+start|stop)
+	if [ "$3" = bad.service ]; then
+		echo "Job for bad.service failed because the control process exited with error code." >&2
+		exit 1
+	fi ;;
+esac`
+
+// actions returns the start and stop calls recorded by the fake, as
+// "verb unit" strings.
+func actions(t *testing.T, logFile string) []string {
+	t.Helper()
+	var got []string
+	for _, call := range fakeInvocations(t, logFile) {
+		if call[0] == "start" || call[0] == "stop" {
+			got = append(got, call[0]+" "+call[2])
+		}
+	}
+	return got
+}
+
+func TestStartGroupOrder(t *testing.T) {
+	logFile := fakeSystemctl(t, groupSystemctl)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	units := []string{"app.service", "cache.service", "network.target", "db.service"}
+	results, err := StartGroup(ctx, units, GroupOptions{Concurrency: 1}, Options{})
+	if err != nil {
+		t.Fatalf("StartGroup returned error: %v", err)
+	}
+	want := []string{"start network.target", "start cache.service", "start db.service", "start app.service"}
+	if got := actions(t, logFile); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+	for i, unit := range []string{"network.target", "cache.service", "db.service", "app.service"} {
+		if results[i] != (GroupResult{Unit: unit}) {
+			t.Errorf("results[%d] = %+v, want success for %s", i, results[i], unit)
+		}
+	}
+}
+
+func TestStartGroupBareNames(t *testing.T) {
+	logFile := fakeSystemctl(t, groupSystemctl)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	results, err := StartGroup(ctx, []string{"app", "db"}, GroupOptions{Concurrency: 1}, Options{})
+	if err != nil {
+		t.Fatalf("StartGroup returned error: %v", err)
+	}
+	want := []string{"start db.service", "start app.service"}
+	if got := actions(t, logFile); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+	if len(results) != 2 || results[0].Unit != "db.service" || results[1].Unit != "app.service" {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func TestStopGroupOrder(t *testing.T) {
+	logFile := fakeSystemctl(t, groupSystemctl)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	units := []string{"network.target", "db.service", "app.service"}
+	if _, err := StopGroup(ctx, units, GroupOptions{Concurrency: 1}, Options{}); err != nil {
+		t.Fatalf("StopGroup returned error: %v", err)
+	}
+	want := []string{"stop app.service", "stop db.service", "stop network.target"}
+	if got := actions(t, logFile); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestStartGroupStopsOnFailure(t *testing.T) {
+	logFile := fakeSystemctl(t, groupSystemctl)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	results, err := StartGroup(ctx, []string{"app.service", "bad.service"}, GroupOptions{}, Options{})
+	if err == nil {
+		t.Errorf("StartGroup returned no error for bad.service")
+	}
+	if len(results) != 2 || results[0].Unit != "bad.service" || results[0].Err == nil {
+		t.Fatalf("results = %+v, want bad.service to fail first", results)
+	}
+	if results[1] != (GroupResult{Unit: "app.service", Skipped: true}) {
+		t.Errorf("results[1] = %+v, want app.service skipped", results[1])
+	}
+	want := []string{"start bad.service"}
+	if got := actions(t, logFile); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestStartGroupContinueOnError(t *testing.T) {
+	fakeSystemctl(t, groupSystemctl)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	units := []string{"app.service", "bad.service", "db.service", "cache.service", "network.target"}
+	results, err := StartGroup(ctx, units, GroupOptions{Concurrency: 2, ContinueOnError: true}, Options{})
+	if err == nil {
+		t.Errorf("StartGroup returned no error for bad.service")
+	}
+	for _, r := range results {
+		if r.Skipped {
+			t.Errorf("%s was skipped", r.Unit)
+		}
+		if (r.Err != nil) != (r.Unit == "bad.service") {
+			t.Errorf("%s: unexpected error %v", r.Unit, r.Err)
+		}
+	}
+	if len(results) != len(units) {
+		t.Errorf("got %d results, want %d", len(results), len(units))
+	}
+}
+
+func TestStartGroupCycle(t *testing.T) {
+	logFile := fakeSystemctl(t, `if [ "$1" = show ]; then
+	printf 'Id=a.service\nAfter=b.service\n\nId=b.service\nAfter=a.service\n'
+fi`)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := StartGroup(ctx, []string{"a.service", "b.service"}, GroupOptions{}, Options{})
+	if !errors.Is(err, ErrOrderingCycle) {
+		t.Errorf("error is %v, but should have been %v", err, ErrOrderingCycle)
+	}
+	if got := actions(t, logFile); len(got) != 0 {
+		t.Errorf("units were started despite the cycle: %v", got)
+	}
+}
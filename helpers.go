@@ -2,13 +2,16 @@ package systemctl
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/taigrr/systemctl/properties"
+	"github.com/taigrr/systemctl/unitfile"
 )
 
 const dateFormat = "Mon 2006-01-02 15:04:05 MST"
@@ -43,7 +46,7 @@ func GetNumRestarts(ctx context.Context, unit string, opts Options) (int, error)
 	// nonexistent/unloaded units. Disambiguate by checking LoadState: if the
 	// unit isn't loaded, the value is meaningless.
 	if restarts == 0 {
-		loadState, loadErr := Show(ctx, unit, properties.LoadState, opts)
+		loadState, loadErr := Show(ctx, unit, properties.LoadState, opts.withoutFlags())
 		if loadErr == nil && loadState == "not-found" {
 			return -1, ErrValueNotSet
 		}
@@ -72,9 +75,23 @@ func GetPID(ctx context.Context, unit string, opts Options) (int, error) {
 	return strconv.Atoi(value)
 }
 
+// ReadUnit returns the parsed fragment and drop-ins of a unit
+// (`systemctl cat [unit]`), in the order systemd applies them.
+// Each file's Path is set to the location it was read from.
+func ReadUnit(ctx context.Context, unit string, opts Options) ([]*unitfile.File, error) {
+	stdout, err := Cat(ctx, unit, opts)
+	if err != nil {
+		return nil, err
+	}
+	return unitfile.ParseCat(stdout)
+}
+
 // GetSocketsForServiceUnit returns the socket units associated with a given service unit.
 func GetSocketsForServiceUnit(ctx context.Context, unit string, opts Options) ([]string, error) {
-	args := prepareArgs("list-sockets", opts, "--all", "--no-legend", "--no-pager")
+	args, err := prepareUnitArgs("list-sockets", opts, nil, []string{"--all", "--no-legend", "--no-pager"}, nil)
+	if err != nil {
+		return []string{}, err
+	}
 	stdout, _, _, err := execute(ctx, args)
 	if err != nil {
 		return []string{}, err
@@ -98,68 +115,246 @@ func GetSocketsForServiceUnit(ctx context.Context, unit string, opts Options) ([
 
 // GetUnits returns a list of all loaded units and their states.
 func GetUnits(ctx context.Context, opts Options) ([]Unit, error) {
-	args := prepareArgs("list-units", opts, "--all", "--no-legend", "--full", "--no-pager")
-	stdout, stderr, _, err := execute(ctx, args)
+	return ListUnits(ctx, ListUnitsOptions{All: true}, opts)
+}
+
+// ListUnits returns the units known to systemd, filtered by lopts
+// (`systemctl list-units`).
+//
+// JSON output is requested from systemctl, with a fallback to parsing the
+// text table on systemd versions which don't support it.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func ListUnits(ctx context.Context, lopts ListUnitsOptions, opts Options, args ...string) ([]Unit, error) {
+	flags := []string{"--full", "--no-pager"}
+	if requireVersion(ctx, versionJSONOutput, "--output=json") == nil {
+		flags = append(flags, "--output=json")
+	}
+	flags = append(flags, lopts.args()...)
+	a, err := prepareUnitArgs("list-units", opts, lopts.Patterns, flags, args)
+	if err != nil {
+		return []Unit{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
 	if err != nil {
 		return []Unit{}, errors.Join(err, filterErr(stderr))
 	}
-	lines := strings.Split(stdout, "\n")
-	units := []Unit{}
-	for _, line := range lines {
-		entry := strings.Fields(line)
-		if len(entry) < 4 {
-			continue
-		}
+	if strings.HasPrefix(strings.TrimSpace(stdout), "[") {
+		return parseUnitsJSON(stdout)
+	}
+	return parseUnits(stdout), nil
+}
+
+func (l ListUnitsOptions) args() []string {
+	var args []string
+	if l.All {
+		args = append(args, "--all")
+	}
+	if l.Failed {
+		args = append(args, "--failed")
+	}
+	if len(l.Types) > 0 {
+		args = append(args, "--type="+strings.Join(l.Types, ","))
+	}
+	if len(l.States) > 0 {
+		args = append(args, "--state="+strings.Join(l.States, ","))
+	}
+	return args
+}
+
+func parseUnitsJSON(stdout string) ([]Unit, error) {
+	var entries []struct {
+		Unit        string  `json:"unit"`
+		Load        string  `json:"load"`
+		Active      string  `json:"active"`
+		Sub         string  `json:"sub"`
+		Job         *string `json:"job"`
+		Description string  `json:"description"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+		return []Unit{}, err
+	}
+	units := make([]Unit, 0, len(entries))
+	for _, e := range entries {
 		unit := Unit{
-			Name:        entry[0],
-			Load:        entry[1],
-			Active:      entry[2],
-			Sub:         entry[3],
-			Description: strings.Join(entry[4:], " "),
+			Name:        e.Unit,
+			Load:        e.Load,
+			Active:      e.Active,
+			Sub:         e.Sub,
+			Description: e.Description,
+		}
+		if e.Job != nil {
+			unit.Job = *e.Job
 		}
 		units = append(units, unit)
 	}
 	return units, nil
 }
 
-// GetMaskedUnits returns a list of all masked unit names.
-func GetMaskedUnits(ctx context.Context, opts Options) ([]string, error) {
-	args := prepareArgs("list-unit-files", opts, "--state=masked")
-	stdout, stderr, _, err := execute(ctx, args)
+// parseUnits parses the text table of list-units. The JOB column is only
+// present while jobs are queued and is empty for units without a job, so
+// the column offsets are taken from the header line. Failed and inactive
+// units are prefixed with a status bullet. Output without a header, as
+// printed with --no-legend, is split on whitespace and has no jobs.
+func parseUnits(stdout string) []Unit {
+	units := []Unit{}
+	jobCol, descCol := -1, -1
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 4 && fields[0] == "UNIT" && fields[1] == "LOAD" && descCol < 0 {
+			header := []rune(line)
+			jobCol = runeIndex(header, "JOB")
+			descCol = runeIndex(header, "DESCRIPTION")
+			continue
+		}
+		if len(fields) == 0 {
+			if descCol >= 0 {
+				// The legend follows the first empty line.
+				break
+			}
+			continue
+		}
+		runes := []rune(line)
+		var job, description string
+		if descCol >= 0 && descCol <= len(runes) {
+			description = strings.TrimSpace(string(runes[descCol:]))
+			end := descCol
+			if jobCol >= 0 && jobCol < descCol {
+				job = strings.TrimSpace(string(runes[jobCol:descCol]))
+				end = jobCol
+			}
+			fields = strings.Fields(string(runes[:end]))
+		}
+		if len(fields) > 0 && isStatusBullet(fields[0]) {
+			fields = fields[1:]
+		}
+		if len(fields) < 4 || !HasValidUnitSuffix(fields[0]) {
+			continue
+		}
+		if descCol < 0 {
+			description = strings.Join(fields[4:], " ")
+		}
+		units = append(units, Unit{
+			Name:        fields[0],
+			Load:        fields[1],
+			Active:      fields[2],
+			Sub:         fields[3],
+			Job:         job,
+			Description: description,
+		})
+	}
+	return units
+}
+
+// isStatusBullet reports whether a field is the status marker systemctl
+// prints in front of failed or inactive units in list output.
+func isStatusBullet(field string) bool {
+	switch field {
+	case "●", "○", "×", "*":
+		return true
+	}
+	return false
+}
+
+// runeIndex returns the index of the first rune of word in line, or -1.
+func runeIndex(line []rune, word string) int {
+	i := strings.Index(string(line), word)
+	if i < 0 {
+		return -1
+	}
+	return len([]rune(string(line)[:i]))
+}
+
+// ListUnitFiles returns the installed unit files along with their
+// enablement state and vendor preset (`systemctl list-unit-files`),
+// filtered by lopts.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func ListUnitFiles(ctx context.Context, lopts ListUnitFilesOptions, opts Options, args ...string) ([]UnitFile, error) {
+	flags := append([]string{"--no-legend", "--full", "--no-pager"}, lopts.args()...)
+	a, err := prepareUnitArgs("list-unit-files", opts, lopts.Patterns, flags, args)
 	if err != nil {
-		return []string{}, errors.Join(err, filterErr(stderr))
+		return []UnitFile{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	if err != nil {
+		return []UnitFile{}, errors.Join(err, filterErr(stderr))
+	}
+	return parseUnitFiles(stdout), nil
+}
+
+func (l ListUnitFilesOptions) args() []string {
+	var args []string
+	if len(l.States) > 0 {
+		states := make([]string, len(l.States))
+		for i, state := range l.States {
+			states[i] = string(state)
+		}
+		args = append(args, "--state="+strings.Join(states, ","))
 	}
-	return parseMaskedUnits(stdout), nil
+	if len(l.Types) > 0 {
+		args = append(args, "--type="+strings.Join(l.Types, ","))
+	}
+	return args
 }
 
-func parseMaskedUnits(stdout string) []string {
+func parseUnitFiles(stdout string) []UnitFile {
 	lines := strings.Split(stdout, "\n")
-	units := []string{}
+	files := []UnitFile{}
 	for _, line := range lines {
 		entry := strings.Fields(line)
-		if len(entry) < 3 {
+		if len(entry) < 2 || !HasValidUnitSuffix(entry[0]) {
 			continue
 		}
-		if entry[1] == "masked" {
-			unit := entry[0]
-			units = append(units, unitNameWithoutSuffix(unit))
+		file := UnitFile{Name: entry[0], State: UnitFileState(entry[1])}
+		if len(entry) > 2 && entry[2] != "-" {
+			file.Preset = entry[2]
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// GetMaskedUnits returns the full names of all masked unit files,
+// including those masked only until the next reboot.
+func GetMaskedUnits(ctx context.Context, opts Options) ([]string, error) {
+	lopts := ListUnitFilesOptions{States: []UnitFileState{UnitFileMasked, UnitFileMaskedRuntime}}
+	files, err := ListUnitFiles(ctx, lopts, opts)
+	if err != nil {
+		return []string{}, err
+	}
+	return maskedUnits(files), nil
+}
+
+func maskedUnits(files []UnitFile) []string {
+	units := []string{}
+	for _, file := range files {
+		if file.State == UnitFileMasked || file.State == UnitFileMaskedRuntime {
+			units = append(units, file.Name)
 		}
 	}
 	return units
 }
 
 func serviceUnitName(unit string) string {
-	if HasValidUnitSuffix(unit) {
-		return unit
-	}
-	return unit + ".service"
+	return withDefaultType(unit, "service")
+}
+
+func targetUnitName(unit string) string {
+	return withDefaultType(unit, "target")
 }
 
+func timerUnitName(unit string) string {
+	return withDefaultType(unit, "timer")
+}
+
+// unitNameWithoutSuffix strips the unit type suffix from a unit name, if it
+// has one.
 func unitNameWithoutSuffix(unit string) string {
-	for _, unitType := range UnitTypes {
-		unit = strings.TrimSuffix(unit, "."+unitType)
+	if !HasValidUnitSuffix(unit) {
+		return unit
 	}
-	return unit
+	return unit[:strings.LastIndexByte(unit, '.')]
 }
 
 // IsSystemd checks if systemd is the current init system by reading /proc/1/comm.
@@ -171,19 +366,15 @@ func IsSystemd() (bool, error) {
 	return strings.TrimSpace(string(b)) == "systemd", nil
 }
 
-// IsMasked checks if a unit is masked.
+// IsMasked checks if a unit is masked. A unit name without a type suffix
+// is taken to be a service, as systemctl does, so "foo" matches
+// foo.service but not foo.socket.
 func IsMasked(ctx context.Context, unit string, opts Options) (bool, error) {
 	units, err := GetMaskedUnits(ctx, opts)
 	if err != nil {
 		return false, err
 	}
-	unit = unitNameWithoutSuffix(unit)
-	for _, u := range units {
-		if u == unit {
-			return true, nil
-		}
-	}
-	return false, nil
+	return slices.Contains(units, serviceUnitName(unit)), nil
 }
 
 // IsRunning checks if a unit's sub-state is "running".
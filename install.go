@@ -0,0 +1,264 @@
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/taigrr/systemctl/properties"
+	"github.com/taigrr/systemctl/unitfile"
+)
+
+// UnitFilePath returns the path a unit file named name is installed to by
+// WriteUnitFile: below /etc/systemd/system for the system manager, or
+// ~/.config/systemd/user for a user manager.
+func UnitFilePath(name string, opts Options) (string, error) {
+	if !HasValidUnitSuffix(name) || strings.ContainsRune(name, '/') {
+		return "", fmt.Errorf("invalid unit file name %q: %w", name, ErrInvalidName)
+	}
+	dir, err := unitConfigDir(opts, false, false)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// WriteUnitFile atomically writes a unit file, such as one rendered by the
+// builders in the unitfile package, into the administrator unit directory
+// of the system or user manager selected by opts. The name must include
+// the unit type suffix, e.g. "app.service". It returns the path written.
+//
+// When root writes a unit for another user's manager (opts.User), the file
+// and any directories created for it are owned by that user.
+//
+// The manager does not pick up the new file until DaemonReload is called.
+func WriteUnitFile(name string, file *unitfile.File, opts Options) (string, error) {
+	path, err := UnitFilePath(name, opts)
+	if err != nil {
+		return "", err
+	}
+	owner, err := unitFileOwner(opts, false)
+	if err != nil {
+		return "", err
+	}
+	if err := writeFileAtomic(path, []byte(file.String()), 0o644, owner); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// rollbackTimeout bounds how long InstallService may spend restoring the
+// previous state, as the caller's context may already have expired.
+const rollbackTimeout = 30 * time.Second
+
+// activePollInterval is how often InstallService checks whether a freshly
+// started service has become active.
+var activePollInterval = 250 * time.Millisecond
+
+// activeTimeout bounds how long InstallService waits for a service to
+// become active, in case the caller's context has no deadline.
+var activeTimeout = 90 * time.Second
+
+// ServiceSpec describes a service installed by InstallService.
+type ServiceSpec struct {
+	// Name of the unit. ".service" is appended if there is no suffix.
+	Name    string
+	Service unitfile.Service
+}
+
+// InstallService installs, enables and (re)starts a service in one call.
+//
+// The unit file is written with WriteUnitFile, the manager is reloaded, and
+// the unit is enabled and restarted. InstallService then waits for the
+// restart job to finish, even if it was queued with WithNoBlock(), and
+// until the unit's ActiveState is "active", or until a oneshot service has
+// exited successfully. ErrUnitNotActive is returned if the unit fails, enters an
+// auto-restart loop, or is still starting after 90 seconds; the wait is
+// bounded by ctx as well.
+//
+// Flags in opts are only passed to the enable and the restart, and each
+// only to those of the two which accept it: WithNow() and WithRuntime()
+// apply to the enable, WithJobMode() to the restart, and WithNoBlock() to
+// both. A flag which neither accepts is rejected with ErrFlagNotAllowed
+// before anything is written.
+//
+// If any step fails, the previous unit file (or its absence) is restored,
+// along with the previous enablement and active state, and the original
+// error is returned joined with any error encountered while rolling back.
+func InstallService(ctx context.Context, spec ServiceSpec, opts Options) error {
+	name := serviceUnitName(spec.Name)
+	file, err := spec.Service.File()
+	if err != nil {
+		return err
+	}
+	path, err := UnitFilePath(name, opts)
+	if err != nil {
+		return err
+	}
+
+	enableOpts, restartOpts, err := installFlags(opts)
+	if err != nil {
+		return err
+	}
+
+	previous, readErr := os.ReadFile(path)
+	if readErr != nil && !errors.Is(readErr, os.ErrNotExist) {
+		return readErr
+	}
+	existed := readErr == nil
+	// Flags are meant for the enable and restart; the queries, reloads and
+	// the rollback run without them.
+	query := opts.withoutFlags()
+	wasEnabled, _ := IsEnabled(ctx, name, query)
+	wasActive, _ := IsActive(ctx, name, query)
+
+	err = installService(ctx, name, file, opts, enableOpts, restartOpts)
+	if err == nil {
+		return nil
+	}
+
+	rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+	var rollbackErrs []error
+	// Disable while the new unit file is still in place, so the symlinks
+	// created for its [Install] section are the ones removed.
+	if disableErr := Disable(rctx, name, query); disableErr != nil && !errors.Is(disableErr, ErrDoesNotExist) {
+		rollbackErrs = append(rollbackErrs, disableErr)
+	}
+	if existed {
+		owner, ownerErr := unitFileOwner(opts, false)
+		rollbackErrs = append(rollbackErrs, ownerErr, writeFileAtomic(path, previous, 0o644, owner))
+	} else if removeErr := os.Remove(path); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+		rollbackErrs = append(rollbackErrs, removeErr)
+	}
+	rollbackErrs = append(rollbackErrs, DaemonReload(rctx, query))
+	if existed {
+		if wasEnabled {
+			rollbackErrs = append(rollbackErrs, Enable(rctx, name, query))
+		}
+		if wasActive {
+			rollbackErrs = append(rollbackErrs, Restart(rctx, name, query))
+		} else {
+			rollbackErrs = append(rollbackErrs, Stop(rctx, name, query))
+		}
+	} else {
+		// The unit file is gone, so stopping it may report
+		// ErrDoesNotExist; that is the state we want anyway.
+		_ = Stop(rctx, name, query)
+		_ = ResetFailed(rctx, name, query)
+	}
+	if rollbackErr := errors.Join(rollbackErrs...); rollbackErr != nil {
+		return errors.Join(err, fmt.Errorf("rollback of %s failed: %w", name, rollbackErr))
+	}
+	return err
+}
+
+// installFlags splits the Flags in opts between the enable and the
+// restart, and checks them for both commands.
+func installFlags(opts Options) (Options, Options, error) {
+	enableOpts, restartOpts := opts.flagsFor("enable"), opts.flagsFor("restart")
+	for _, f := range opts.Flags {
+		if !slices.Contains(enableOpts.Flags, f) && !slices.Contains(restartOpts.Flags, f) {
+			return Options{}, Options{}, fmt.Errorf("%s can't be used with enable or restart: %w", f.name, ErrFlagNotAllowed)
+		}
+	}
+	if _, err := flagArgs("enable", enableOpts.Flags); err != nil {
+		return Options{}, Options{}, err
+	}
+	if _, err := flagArgs("restart", restartOpts.Flags); err != nil {
+		return Options{}, Options{}, err
+	}
+	return enableOpts, restartOpts, nil
+}
+
+func installService(ctx context.Context, name string, file *unitfile.File, opts Options, enableOpts Options, restartOpts Options) error {
+	if _, err := WriteUnitFile(name, file, opts); err != nil {
+		return err
+	}
+	if err := DaemonReload(ctx, opts.withoutFlags()); err != nil {
+		return err
+	}
+	if err := Enable(ctx, name, enableOpts); err != nil {
+		return err
+	}
+	if err := Restart(ctx, name, restartOpts); err != nil {
+		return err
+	}
+	return waitForActive(ctx, name, opts.withoutFlags())
+}
+
+var activeStateProperties = []properties.Property{properties.ActiveState, properties.SubState, properties.Result, properties.Job}
+
+func waitForActive(ctx context.Context, unit string, opts Options) error {
+	wctx, cancel := context.WithTimeout(ctx, activeTimeout)
+	defer cancel()
+	ticker := time.NewTicker(activePollInterval)
+	defer ticker.Stop()
+	for {
+		values, err := ShowUnits(wctx, []string{unit}, activeStateProperties, opts)
+		if err != nil {
+			return err
+		}
+		state := values[unit]
+		active, sub, result := state[properties.ActiveState], state[properties.SubState], state[properties.Result]
+		// Job is set while the restart job is still queued, e.g. after
+		// WithNoBlock(); until it runs, the state is that of the previous
+		// run, or of a unit which never ran.
+		job := state[properties.Job]
+		switch {
+		case job != "" && job != "0":
+		case active == "active":
+			return nil
+		case active == "inactive" && result == "success":
+			// A oneshot service without RemainAfterExit= which ran and
+			// exited cleanly.
+			return nil
+		case active == "failed", active == "inactive":
+			return fmt.Errorf("%s is %s (%s): %w", unit, active, result, ErrUnitNotActive)
+		case sub == "auto-restart":
+			return fmt.Errorf("%s is restarting after %s: %w", unit, result, ErrUnitNotActive)
+		}
+		select {
+		case <-wctx.Done():
+			if ctx.Err() != nil {
+				return errors.Join(ErrExecTimeout, ctx.Err())
+			}
+			return fmt.Errorf("%s is still %s after %s: %w", unit, active, activeTimeout, ErrUnitNotActive)
+		case <-ticker.C:
+		}
+	}
+}
+
+// UninstallService stops and disables a service, removes its unit file and
+// drop-ins from the administrator unit directory, reloads the manager and
+// resets the unit's failed state. A service which is already partially or
+// fully uninstalled is not an error.
+func UninstallService(ctx context.Context, name string, opts Options) error {
+	name = serviceUnitName(name)
+	path, err := UnitFilePath(name, opts)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	ignoreMissing := func(err error) {
+		if err != nil && !errors.Is(err, ErrDoesNotExist) && !errors.Is(err, ErrUnitNotLoaded) {
+			errs = append(errs, err)
+		}
+	}
+	ignoreMissing(Stop(ctx, name, opts))
+	ignoreMissing(Disable(ctx, name, opts))
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		errs = append(errs, err)
+	}
+	if err := os.RemoveAll(path + ".d"); err != nil {
+		errs = append(errs, err)
+	}
+	ignoreMissing(DaemonReload(ctx, opts.withoutFlags()))
+	ignoreMissing(ResetFailed(ctx, name, opts.withoutFlags()))
+	return errors.Join(errs...)
+}
@@ -0,0 +1,270 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/taigrr/systemctl/unitfile"
+)
+
+// fakeServiceManager fakes the answers to is-enabled and is-active, and
+// reports the given "ActiveState SubState Result" after the restart.
+func fakeServiceManager(t *testing.T, enabled string, active string, state string) string {
+	t.Helper()
+	return fakeSystemctl(t, `case "$1" in
+is-enabled) echo `+enabled+` ;;
+is-active) echo `+active+` ;;
+show)
+	set -- `+state+`
+	printf 'Id=app.service\nActiveState=%s\nSubState=%s\nResult=%s\n' "$1" "$2" "$3" ;;
+esac`)
+}
+
+func verbs(calls [][]string) []string {
+	got := []string{}
+	for _, call := range calls {
+		got = append(got, call[0])
+	}
+	return got
+}
+
+func TestInstallService(t *testing.T) {
+	tempDir := useTempUnitDirs(t)
+	logFile := fakeServiceManager(t, "disabled", "inactive", "active running success")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	spec := ServiceSpec{
+		Name:    "app",
+		Service: unitfile.Service{ExecStart: []string{"/usr/bin/app"}},
+	}
+	if err := InstallService(ctx, spec, Options{}); err != nil {
+		t.Fatalf("InstallService returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "etc/systemd/system/app.service")); err != nil {
+		t.Errorf("unit file not installed: %v", err)
+	}
+	want := []string{"is-enabled", "is-active", "daemon-reload", "enable", "restart", "show"}
+	if got := verbs(fakeInvocations(t, logFile)); !reflect.DeepEqual(got, want) {
+		t.Errorf("verbs = %v, want %v", got, want)
+	}
+}
+
+func TestInstallServiceFlags(t *testing.T) {
+	useTempUnitDirs(t)
+	logFile := fakeServiceManager(t, "disabled", "inactive", "active running success")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	spec := ServiceSpec{Name: "app", Service: unitfile.Service{ExecStart: []string{"/usr/bin/app"}}}
+	opts := Options{Flags: []Flag{WithNoBlock(), WithRuntime(), WithJobMode(JobModeFail)}}
+	if err := InstallService(ctx, spec, opts); err != nil {
+		t.Fatalf("InstallService returned error: %v", err)
+	}
+	want := [][]string{
+		{"is-enabled", "--system", "app.service"},
+		{"is-active", "--system", "app.service"},
+		{"daemon-reload", "--system"},
+		{"enable", "--system", "app.service", "--no-block", "--runtime"},
+		{"restart", "--system", "app.service", "--no-block", "--job-mode=fail"},
+		{"show", "--system", "app.service", "--property=Id,ActiveState,SubState,Result,Job"},
+	}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, want) {
+		t.Errorf("invocations = %v, want %v", got, want)
+	}
+}
+
+func TestInstallServiceRejectsFlags(t *testing.T) {
+	tempDir := useTempUnitDirs(t)
+	logFile := fakeServiceManager(t, "disabled", "inactive", "active running success")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	spec := ServiceSpec{Name: "app", Service: unitfile.Service{ExecStart: []string{"/usr/bin/app"}}}
+	for _, flags := range [][]Flag{
+		{WithSignal("SIGHUP")},
+		{WithNoBlock(), WithNoBlock()},
+		{WithJobMode("sometimes")},
+	} {
+		if err := InstallService(ctx, spec, Options{Flags: flags}); !errors.Is(err, ErrFlagNotAllowed) {
+			t.Errorf("%v: error is %v, but should have been %v", flags, err, ErrFlagNotAllowed)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "etc/systemd/system/app.service")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("unit file written despite rejected flags: %v", err)
+	}
+	if got := fakeInvocations(t, logFile); len(got) != 0 {
+		t.Errorf("invocations = %v, want none", got)
+	}
+}
+
+func TestInstallServiceWaitsForActive(t *testing.T) {
+	useTempUnitDirs(t)
+	original := activeTimeout
+	activeTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { activeTimeout = original })
+
+	tests := []struct {
+		name  string
+		state string
+		ok    bool
+	}{
+		{"running", "active running success", true},
+		{"oneshot exited", "inactive dead success", true},
+		{"oneshot failed", "inactive dead exit-code", false},
+		{"failed", "failed failed exit-code", false},
+		{"auto-restart", "activating auto-restart exit-code", false},
+		{"stuck activating", "activating start success", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeServiceManager(t, "disabled", "inactive", tt.state)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			spec := ServiceSpec{Name: "app", Service: unitfile.Service{ExecStart: []string{"/usr/bin/app"}}}
+			err := InstallService(ctx, spec, Options{})
+			if tt.ok && err != nil {
+				t.Errorf("InstallService returned error: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrUnitNotActive) {
+				t.Errorf("error is %v, but should have been %v", err, ErrUnitNotActive)
+			}
+		})
+	}
+}
+
+func TestInstallServiceWaitsForQueuedJob(t *testing.T) {
+	tempDir := useTempUnitDirs(t)
+	original := activePollInterval
+	activePollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { activePollInterval = original })
+	// The unit still shows its previous clean exit while the restart job
+	// queued by --no-block waits, and fails once the job has run.
+	counter := filepath.Join(tempDir, "shows")
+	logFile := fakeSystemctl(t, `case "$1" in
+is-enabled) echo disabled ;;
+is-active) echo inactive ;;
+show)
+	if [ -e `+counter+` ]; then
+		printf 'Id=app.service\nActiveState=failed\nSubState=failed\nResult=exit-code\n'
+	else
+		touch `+counter+`
+		printf 'Id=app.service\nActiveState=inactive\nSubState=dead\nResult=success\nJob=42\n'
+	fi ;;
+esac`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	spec := ServiceSpec{Name: "app", Service: unitfile.Service{ExecStart: []string{"/usr/bin/app"}}}
+	err := InstallService(ctx, spec, Options{Flags: []Flag{WithNoBlock()}})
+	if !errors.Is(err, ErrUnitNotActive) {
+		t.Fatalf("error is %v, but should have been %v", err, ErrUnitNotActive)
+	}
+	want := []string{
+		"is-enabled", "is-active", "daemon-reload", "enable", "restart", "show", "show",
+		"disable", "daemon-reload", "stop", "reset-failed",
+	}
+	if got := verbs(fakeInvocations(t, logFile)); !reflect.DeepEqual(got, want) {
+		t.Errorf("verbs = %v, want %v", got, want)
+	}
+}
+
+func TestInstallServiceRollsBackNewUnit(t *testing.T) {
+	tempDir := useTempUnitDirs(t)
+	logFile := fakeServiceManager(t, "disabled", "inactive", "failed failed exit-code")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	spec := ServiceSpec{
+		Name:    "app.service",
+		Service: unitfile.Service{ExecStart: []string{"/usr/bin/app"}},
+	}
+	err := InstallService(ctx, spec, Options{})
+	if !errors.Is(err, ErrUnitNotActive) {
+		t.Fatalf("error is %v, but should have been %v", err, ErrUnitNotActive)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "etc/systemd/system/app.service")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("unit file not removed on rollback: %v", err)
+	}
+	want := []string{
+		"is-enabled", "is-active", "daemon-reload", "enable", "restart", "show",
+		"disable", "daemon-reload", "stop", "reset-failed",
+	}
+	if got := verbs(fakeInvocations(t, logFile)); !reflect.DeepEqual(got, want) {
+		t.Errorf("verbs = %v, want %v", got, want)
+	}
+}
+
+func TestInstallServiceRestoresPreviousUnit(t *testing.T) {
+	tempDir := useTempUnitDirs(t)
+	path := filepath.Join(tempDir, "etc/systemd/system/app.service")
+	previous := "[Service]\nExecStart=/usr/bin/app --old\n"
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("create unit dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(previous), 0o644); err != nil {
+		t.Fatalf("write previous unit: %v", err)
+	}
+	logFile := fakeServiceManager(t, "enabled", "active", "failed failed exit-code")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	spec := ServiceSpec{
+		Name:    "app",
+		Service: unitfile.Service{ExecStart: []string{"/usr/bin/app", "--new"}},
+	}
+	if err := InstallService(ctx, spec, Options{}); !errors.Is(err, ErrUnitNotActive) {
+		t.Fatalf("error is %v, but should have been %v", err, ErrUnitNotActive)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil || string(b) != previous {
+		t.Errorf("unit file = %q, %v, want %q", b, err, previous)
+	}
+	want := []string{
+		"is-enabled", "is-active", "daemon-reload", "enable", "restart", "show",
+		"disable", "daemon-reload", "enable", "restart",
+	}
+	if got := verbs(fakeInvocations(t, logFile)); !reflect.DeepEqual(got, want) {
+		t.Errorf("verbs = %v, want %v", got, want)
+	}
+}
+
+func TestUninstallService(t *testing.T) {
+	tempDir := useTempUnitDirs(t)
+	path := filepath.Join(tempDir, "etc/systemd/system/app.service")
+	if err := os.MkdirAll(path+".d", 0o755); err != nil {
+		t.Fatalf("create drop-in dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("[Service]\n"), 0o644); err != nil {
+		t.Fatalf("write unit: %v", err)
+	}
+	logFile := fakeSystemctl(t, ``)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := UninstallService(ctx, "app", Options{}); err != nil {
+		t.Fatalf("UninstallService returned error: %v", err)
+	}
+	for _, p := range []string{path, path + ".d"} {
+		if _, err := os.Stat(p); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s not removed: %v", p, err)
+		}
+	}
+	want := [][]string{
+		{"stop", "--system", "app.service"},
+		{"disable", "--system", "app.service"},
+		{"daemon-reload", "--system"},
+		{"reset-failed", "--system", "app.service"},
+	}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, want) {
+		t.Errorf("invocations = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package systemctl
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/taigrr/systemctl/unitfile"
+)
+
+func TestWriteUnitFile(t *testing.T) {
+	tempDir := useTempUnitDirs(t)
+	file, err := unitfile.Service{ExecStart: []string{"/usr/bin/app"}}.File()
+	if err != nil {
+		t.Fatalf("render service: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{name: "system", opts: Options{}, want: filepath.Join(tempDir, "etc/systemd/system/app.service")},
+		{name: "user", opts: Options{UserMode: true}, want: filepath.Join(tempDir, "config/systemd/user/app.service")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := WriteUnitFile("app.service", file, tt.opts)
+			if err != nil {
+				t.Fatalf("WriteUnitFile returned error: %v", err)
+			}
+			if path != tt.want {
+				t.Errorf("WriteUnitFile path = %q, want %q", path, tt.want)
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read unit file: %v", err)
+			}
+			if string(b) != file.String() {
+				t.Errorf("unit file content = %q, want %q", b, file.String())
+			}
+		})
+	}
+
+	for _, name := range []string{"app", "../app.service"} {
+		if _, err := WriteUnitFile(name, file, Options{}); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("WriteUnitFile(%q) error is %v, but should have been %v", name, err, ErrInvalidName)
+		}
+	}
+}
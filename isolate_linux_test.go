@@ -0,0 +1,89 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIsolate(t *testing.T) {
+	tests := []struct {
+		name       string
+		unit       string
+		loadState  string
+		canIsolate string
+		err        error
+		want       [][]string
+	}{
+		{
+			name:       "isolation allowed",
+			unit:       "rescue",
+			loadState:  "loaded",
+			canIsolate: "yes",
+			err:        nil,
+			want: [][]string{
+				{"show", "--system", "rescue.target", "--property", "LoadState"},
+				{"show", "--system", "rescue.target", "--property", "CanIsolate"},
+				{"isolate", "--system", "rescue.target"},
+			},
+		},
+		{
+			name:       "isolation refused",
+			unit:       "network.target",
+			loadState:  "loaded",
+			canIsolate: "no",
+			err:        ErrIsolateNotAllowed,
+			want: [][]string{
+				{"show", "--system", "network.target", "--property", "LoadState"},
+				{"show", "--system", "network.target", "--property", "CanIsolate"},
+			},
+		},
+		{
+			name:       "missing target",
+			unit:       "nonexistant.target",
+			loadState:  "not-found",
+			canIsolate: "no",
+			err:        ErrDoesNotExist,
+			want: [][]string{
+				{"show", "--system", "nonexistant.target", "--property", "LoadState"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logFile := fakeSystemctl(t, `case "$5" in
+LoadState) echo "LoadState=`+tt.loadState+`" ;;
+CanIsolate) echo "CanIsolate=`+tt.canIsolate+`" ;;
+esac`)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			err := Isolate(ctx, tt.unit, Options{})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("error is %v, but should have been %v", err, tt.err)
+			}
+			if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("invocations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDefault(t *testing.T) {
+	fakeSystemctl(t, `echo graphical.target`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	target, err := GetDefault(ctx, Options{})
+	if err != nil {
+		t.Fatalf("GetDefault returned error: %v", err)
+	}
+	if target != "graphical.target" {
+		t.Errorf("GetDefault = %q, want %q", target, "graphical.target")
+	}
+}
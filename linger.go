@@ -0,0 +1,62 @@
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+)
+
+// EnableLinger enables lingering for the given user (`loginctl enable-linger`).
+//
+// With lingering enabled, a user manager is spawned at boot and kept around
+// after logouts, so services enabled with Options{UserMode: true} survive
+// the user logging out and start on boot.
+func EnableLinger(ctx context.Context, username string) error {
+	return setLinger(ctx, "enable-linger", username)
+}
+
+// DisableLinger disables lingering for the given user (`loginctl disable-linger`).
+//
+// The user's manager, and any user services it runs, will be stopped once
+// the user has no remaining sessions.
+func DisableLinger(ctx context.Context, username string) error {
+	return setLinger(ctx, "disable-linger", username)
+}
+
+// IsLingering checks whether lingering is enabled for the given user by
+// looking for the user's entry in /var/lib/systemd/linger.
+//
+// Returns ErrExecTimeout if ctx is already done.
+func IsLingering(ctx context.Context, username string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, errors.Join(ErrExecTimeout, err)
+	}
+	if _, err := user.Lookup(username); err != nil {
+		return false, err
+	}
+	_, err := os.Stat(filepath.Join(lingerDir, username))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, os.ErrNotExist):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func setLinger(ctx context.Context, verb string, username string) error {
+	if loginctl == "" {
+		return ErrLoginctlNotInstalled
+	}
+	if _, err := user.Lookup(username); err != nil {
+		return err
+	}
+	_, stderr, _, err := executeCommand(ctx, loginctl, []string{verb, username})
+	if err != nil {
+		return errors.Join(err, filterErr(stderr))
+	}
+	return nil
+}
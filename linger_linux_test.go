@@ -0,0 +1,97 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLingerBuildsExpectedCommand(t *testing.T) {
+	cur, err := user.Current()
+	if err != nil {
+		t.Skip("cannot determine current user")
+	}
+	tests := []struct {
+		name string
+		fn   func(ctx context.Context, username string) error
+		want []string
+	}{
+		{name: "enable", fn: EnableLinger, want: []string{"enable-linger", cur.Username}},
+		{name: "disable", fn: DisableLinger, want: []string{"disable-linger", cur.Username}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			logFile := filepath.Join(tempDir, "args.log")
+			fakeLoginctl := filepath.Join(tempDir, "loginctl")
+			script := "#!/bin/sh\nprintf '%s\\n' \"$@\" > '" + logFile + "'\n"
+
+			if err := os.WriteFile(fakeLoginctl, []byte(script), 0o755); err != nil {
+				t.Fatalf("write fake loginctl: %v", err)
+			}
+
+			original := loginctl
+			loginctl = fakeLoginctl
+			t.Cleanup(func() {
+				loginctl = original
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			if err := tt.fn(ctx, cur.Username); err != nil {
+				t.Fatalf("%s returned error: %v", tt.name, err)
+			}
+
+			gotBytes, err := os.ReadFile(logFile)
+			if err != nil {
+				t.Fatalf("read captured args: %v", err)
+			}
+			got := strings.Fields(strings.TrimSpace(string(gotBytes)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("loginctl args = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsLingering(t *testing.T) {
+	cur, err := user.Current()
+	if err != nil {
+		t.Skip("cannot determine current user")
+	}
+	original := lingerDir
+	lingerDir = t.TempDir()
+	t.Cleanup(func() {
+		lingerDir = original
+	})
+
+	lingering, err := IsLingering(t.Context(), cur.Username)
+	if err != nil || lingering {
+		t.Fatalf("IsLingering = %v, %v, want false, nil", lingering, err)
+	}
+	if err := os.WriteFile(filepath.Join(lingerDir, cur.Username), nil, 0o644); err != nil {
+		t.Fatalf("create linger file: %v", err)
+	}
+	lingering, err = IsLingering(t.Context(), cur.Username)
+	if err != nil || !lingering {
+		t.Fatalf("IsLingering = %v, %v, want true, nil", lingering, err)
+	}
+	if _, err := IsLingering(t.Context(), "nonexistant-user"); err == nil {
+		t.Errorf("IsLingering returned no error for an unknown user")
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := IsLingering(ctx, cur.Username); !errors.Is(err, ErrExecTimeout) {
+		t.Errorf("error is %v, but should have been %v", err, ErrExecTimeout)
+	}
+}
@@ -0,0 +1,84 @@
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taigrr/systemctl/properties"
+)
+
+// ManagerProperties describes the service manager itself, as shown by
+// `systemctl show` without a unit.
+type ManagerProperties struct {
+	// Version is the systemd version, e.g. "255.4-1ubuntu8".
+	Version string
+	// Features lists the compile-time features, e.g. "+PAM" or "-SELINUX".
+	Features []string
+	// Virtualization is the virtualization or container technology
+	// detected, e.g. "kvm" or "docker", or empty on bare metal.
+	Virtualization string
+	// NFailedUnits is the number of units in the failed state.
+	NFailedUnits int
+	// NJobs is the number of queued jobs.
+	NJobs int
+	// UserspaceTimestamp is when the manager started.
+	UserspaceTimestamp time.Time
+	// FinishTimestamp is when startup finished, or the zero Time while the
+	// system is still booting.
+	FinishTimestamp time.Time
+}
+
+var managerProperties = []properties.Property{
+	properties.Version,
+	properties.Features,
+	properties.Virtualization,
+	properties.NFailedUnits,
+	properties.NJobs,
+	properties.UserspaceTimestamp,
+	properties.FinishTimestamp,
+}
+
+// GetManagerProperties returns the version, features, virtualization, job
+// and failed unit counts and startup timestamps of the service manager.
+func GetManagerProperties(ctx context.Context, opts Options) (ManagerProperties, error) {
+	names := make([]string, len(managerProperties))
+	for i, p := range managerProperties {
+		names[i] = string(p)
+	}
+	a, err := prepareUnitArgs("show", opts, nil, []string{"--property=" + strings.Join(names, ",")}, nil)
+	if err != nil {
+		return ManagerProperties{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	if err != nil {
+		return ManagerProperties{}, errors.Join(err, filterErr(stderr))
+	}
+	return parseManagerProperties(parseProperties(stdout))
+}
+
+func parseManagerProperties(values map[properties.Property]string) (ManagerProperties, error) {
+	var (
+		m   ManagerProperties
+		err error
+	)
+	m.Version = values[properties.Version]
+	m.Features = strings.Fields(values[properties.Features])
+	m.Virtualization = values[properties.Virtualization]
+	if m.NFailedUnits, err = strconv.Atoi(values[properties.NFailedUnits]); err != nil {
+		return ManagerProperties{}, fmt.Errorf("%s: %w", properties.NFailedUnits, err)
+	}
+	if m.NJobs, err = strconv.Atoi(values[properties.NJobs]); err != nil {
+		return ManagerProperties{}, fmt.Errorf("%s: %w", properties.NJobs, err)
+	}
+	if m.UserspaceTimestamp, err = parseTimestamp(values[properties.UserspaceTimestamp]); err != nil {
+		return ManagerProperties{}, fmt.Errorf("%s: %w", properties.UserspaceTimestamp, err)
+	}
+	if m.FinishTimestamp, err = parseTimestamp(values[properties.FinishTimestamp]); err != nil {
+		return ManagerProperties{}, fmt.Errorf("%s: %w", properties.FinishTimestamp, err)
+	}
+	return m, nil
+}
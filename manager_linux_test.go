@@ -0,0 +1,84 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIsSystemRunning(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    SystemState
+		wantErr bool
+	}{
+		{"running", `echo running`, SystemRunning, false},
+		{"degraded", "echo degraded\nexit 1", SystemDegraded, false},
+		{"offline", "echo offline\nexit 1", SystemOffline, false},
+		{"garbage", "echo confused\nexit 1", SystemUnknown, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeSystemctl(t, tt.body)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := IsSystemRunning(ctx, Options{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsSystemRunning returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsSystemRunning = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	logFile := fakeSystemctl(t, `echo starting`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := IsSystemRunning(ctx, Options{Flags: []Flag{WithWait()}}); err != nil {
+		t.Errorf("IsSystemRunning returned error: %v", err)
+	}
+	wantArgs := [][]string{{"is-system-running", "--system", "--wait"}}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
+
+func TestGetManagerProperties(t *testing.T) {
+	logFile := fakeSystemctl(t, `printf 'Version=255.4-1ubuntu8\n'
+printf 'Features=+PAM +AUDIT -SELINUX +SECCOMP default-hierarchy=unified\n'
+printf 'Virtualization=kvm\n'
+printf 'NFailedUnits=2\n'
+printf 'NJobs=0\n'
+printf 'UserspaceTimestamp=Mon 2024-06-03 08:00:01 UTC\n'
+printf 'FinishTimestamp=\n'`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	got, err := GetManagerProperties(ctx, Options{UserMode: true})
+	if err != nil {
+		t.Fatalf("GetManagerProperties returned error: %v", err)
+	}
+	want := ManagerProperties{
+		Version:            "255.4-1ubuntu8",
+		Features:           []string{"+PAM", "+AUDIT", "-SELINUX", "+SECCOMP", "default-hierarchy=unified"},
+		Virtualization:     "kvm",
+		NFailedUnits:       2,
+		UserspaceTimestamp: got.UserspaceTimestamp,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetManagerProperties = %+v, want %+v", got, want)
+	}
+	if got.UserspaceTimestamp.UTC() != time.Date(2024, 6, 3, 8, 0, 1, 0, time.UTC) {
+		t.Errorf("UserspaceTimestamp = %v", got.UserspaceTimestamp)
+	}
+	wantArgs := [][]string{{"show", "--user", "--property=Version,Features,Virtualization,NFailedUnits,NJobs,UserspaceTimestamp,FinishTimestamp"}}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
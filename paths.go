@@ -0,0 +1,158 @@
+package systemctl
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+)
+
+var (
+	// systemConfigDir holds local configuration for the system manager.
+	systemConfigDir = "/etc/systemd/system"
+	// systemRuntimeDir holds runtime configuration for the system manager.
+	systemRuntimeDir = "/run/systemd/system"
+	// globalConfigDir holds local configuration shared by all user managers.
+	globalConfigDir = "/etc/systemd/user"
+	// globalRuntimeDir holds runtime configuration shared by all user managers.
+	globalRuntimeDir = "/run/systemd/user"
+)
+
+// unitConfigDir returns the directory administrator-supplied unit files and
+// drop-ins are written to, following the same rules as `systemctl edit`.
+//
+// For the system manager this is /etc/systemd/system, or /run/systemd/system
+// if runtime is set. For a user manager it is ~/.config/systemd/user, or
+// $XDG_RUNTIME_DIR/systemd/user if runtime is set. If global is set, the
+// directories shared by all user managers are returned instead.
+func unitConfigDir(opts Options, runtime bool, global bool) (string, error) {
+	switch {
+	case global && runtime:
+		return globalRuntimeDir, nil
+	case global:
+		return globalConfigDir, nil
+	case !opts.UserMode && runtime:
+		return systemRuntimeDir, nil
+	case !opts.UserMode:
+		return systemConfigDir, nil
+	}
+
+	username := opts.User
+	if username == "" {
+		cur, err := user.Current()
+		if err != nil {
+			return "", err
+		}
+		username = cur.Username
+	}
+	if runtime {
+		dir, err := userRuntimeDir(username)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "systemd", "user"), nil
+	}
+	if opts.User == "" {
+		if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+			return filepath.Join(dir, "systemd", "user"), nil
+		}
+	}
+	u, err := user.Lookup(username)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(u.HomeDir, ".config", "systemd", "user"), nil
+}
+
+// fileOwner is the user and group which written files and created
+// directories are handed over to.
+type fileOwner struct {
+	uid int
+	gid int
+}
+
+// unitFileOwner returns the owner of files written to the unit directories
+// of opts.User, or nil if files should keep the owner of the process. When
+// root writes into another user's home, the files are handed over to that
+// user, who could otherwise neither edit nor remove them.
+func unitFileOwner(opts Options, global bool) (*fileOwner, error) {
+	if !opts.UserMode || opts.User == "" || global || os.Geteuid() != 0 {
+		return nil, nil
+	}
+	u, err := user.Lookup(opts.User)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return nil, err
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return nil, err
+	}
+	return &fileOwner{uid: uid, gid: gid}, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so readers never observe a partially written file. If
+// owner is not nil, the file and any directories created for it are
+// chowned to owner.
+func writeFileAtomic(path string, data []byte, perm os.FileMode, owner *fileOwner) error {
+	dir := filepath.Dir(path)
+	if err := mkdirAllOwned(dir, owner); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if owner != nil {
+		if err := tmp.Chown(owner.uid, owner.gid); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
+
+// mkdirAllOwned is os.MkdirAll, but chowns the directories it creates to
+// owner if owner is not nil. Existing directories are left alone.
+func mkdirAllOwned(dir string, owner *fileOwner) error {
+	var created []string
+	if owner != nil {
+		for d := dir; ; d = filepath.Dir(d) {
+			if _, err := os.Stat(d); err == nil || filepath.Dir(d) == d {
+				break
+			}
+			created = append(created, d)
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, d := range created {
+		if err := os.Chown(d, owner.uid, owner.gid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
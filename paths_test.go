@@ -0,0 +1,74 @@
+package systemctl
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+func lookupNobody(t *testing.T) *fileOwner {
+	t.Helper()
+	if os.Geteuid() != 0 {
+		t.Skip("changing file ownership requires root")
+	}
+	u, err := user.Lookup("nobody")
+	if err != nil {
+		t.Skipf("no nobody user: %v", err)
+	}
+	uid, _ := strconv.Atoi(u.Uid)
+	gid, _ := strconv.Atoi(u.Gid)
+	return &fileOwner{uid: uid, gid: gid}
+}
+
+func TestUnitFileOwner(t *testing.T) {
+	nobody := lookupNobody(t)
+	tests := []struct {
+		name   string
+		opts   Options
+		global bool
+		want   *fileOwner
+	}{
+		{name: "system", opts: Options{}},
+		{name: "current user", opts: Options{UserMode: true}},
+		{name: "other user", opts: Options{UserMode: true, User: "nobody"}, want: nobody},
+		{name: "global", opts: Options{UserMode: true, User: "nobody"}, global: true},
+	}
+	for _, tt := range tests {
+		got, err := unitFileOwner(tt.opts, tt.global)
+		if err != nil {
+			t.Errorf("%s: unitFileOwner returned error: %v", tt.name, err)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("%s: unitFileOwner = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteFileAtomicOwner(t *testing.T) {
+	nobody := lookupNobody(t)
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "config", "systemd", "user", "app.service")
+	if err := writeFileAtomic(path, []byte("[Service]\n"), 0o644, nobody); err != nil {
+		t.Fatalf("writeFileAtomic returned error: %v", err)
+	}
+
+	owners := map[string]int{
+		tempDir:                          0,
+		filepath.Join(tempDir, "config"): nobody.uid,
+		filepath.Dir(path):               nobody.uid,
+		path:                             nobody.uid,
+	}
+	for p, want := range owners {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatalf("stat %s: %v", p, err)
+		}
+		if uid := int(info.Sys().(*syscall.Stat_t).Uid); uid != want {
+			t.Errorf("%s is owned by %d, want %d", p, uid, want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// PathWatch is a path watched by a path unit, as listed by
+// `systemctl list-paths`.
+type PathWatch struct {
+	// Path is the watched file system path.
+	Path string
+	// Condition is the kind of watch, e.g. "PathExists" or
+	// "DirectoryNotEmpty".
+	Condition string
+	// Unit is the name of the path unit, e.g. "cups.path".
+	Unit string
+	// Activates is the unit started when the condition is met.
+	Activates string
+}
+
+// ListPaths returns the paths watched by all loaded path units, including
+// inactive ones (`systemctl list-paths --all`). It requires systemd 254 or
+// newer, and returns ErrUnsupportedBySystemd on older versions.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func ListPaths(ctx context.Context, opts Options, args ...string) ([]PathWatch, error) {
+	a, err := prepareUnitArgs("list-paths", opts, nil, []string{"--all", "--no-legend", "--full", "--no-pager"}, args)
+	if err != nil {
+		return []PathWatch{}, err
+	}
+	if err := requireVersion(ctx, versionListPaths, "list-paths"); err != nil {
+		return []PathWatch{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	if err != nil {
+		return []PathWatch{}, errors.Join(err, filterErr(stderr))
+	}
+	return parsePaths(stdout), nil
+}
+
+// parsePaths parses the PATH, CONDITION, UNIT and ACTIVATES columns of
+// `systemctl list-paths --no-legend`. The last three columns never contain
+// spaces, so they are cut off the end of the line and the rest is taken
+// verbatim as the path, which may contain any whitespace.
+func parsePaths(stdout string) []PathWatch {
+	paths := []PathWatch{}
+	for _, line := range strings.Split(stdout, "\n") {
+		if !strings.HasPrefix(line, "/") {
+			continue
+		}
+		rest := line
+		var columns [3]string
+		for i := len(columns) - 1; i >= 0; i-- {
+			rest = strings.TrimRight(rest, " \t")
+			j := strings.LastIndexAny(rest, " \t")
+			if j < 0 {
+				break
+			}
+			rest, columns[i] = rest[:j], rest[j+1:]
+		}
+		path := strings.TrimRight(rest, " \t")
+		if columns[0] == "" || path == "" {
+			continue
+		}
+		paths = append(paths, PathWatch{
+			Path:      path,
+			Condition: columns[0],
+			Unit:      columns[1],
+			Activates: columns[2],
+		})
+	}
+	return paths
+}
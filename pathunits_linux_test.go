@@ -0,0 +1,29 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListPaths(t *testing.T) {
+	logFile := fakeSystemctl(t, `printf '/run/systemd/ask-password DirectoryNotEmpty systemd-ask-password-wall.path systemd-ask-password-wall.service\n'`)
+	fakeSystemdVersion(t, &SystemdVersion{Major: 255})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	paths, err := ListPaths(ctx, Options{})
+	if err != nil {
+		t.Fatalf("ListPaths returned error: %v", err)
+	}
+	if len(paths) != 1 || paths[0].Unit != "systemd-ask-password-wall.path" {
+		t.Errorf("ListPaths = %+v", paths)
+	}
+	wantArgs := [][]string{{"list-paths", "--system", "--all", "--no-legend", "--full", "--no-pager"}}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
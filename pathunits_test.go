@@ -0,0 +1,21 @@
+package systemctl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePaths(t *testing.T) {
+	stdout := "/run/systemd/ask-password DirectoryNotEmpty systemd-ask-password-console.path systemd-ask-password-console.service\n" +
+		"/srv/My  Drop/incoming PathChanged           upload.path                       upload.service\n" +
+		"/var/spool/x.path\n" +
+		"\n" +
+		"2 paths listed.\n"
+	want := []PathWatch{
+		{Path: "/run/systemd/ask-password", Condition: "DirectoryNotEmpty", Unit: "systemd-ask-password-console.path", Activates: "systemd-ask-password-console.service"},
+		{Path: "/srv/My  Drop/incoming", Condition: "PathChanged", Unit: "upload.path", Activates: "upload.service"},
+	}
+	if got := parsePaths(stdout); !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePaths = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// UnitResult is the outcome of an operation on one unit matched by a
+// pattern.
+type UnitResult struct {
+	Unit string
+	Err  error
+}
+
+// isGlob reports whether a unit argument is a shell-style pattern, which
+// systemctl would match against unit names itself.
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// ExpandUnits returns the names of all loaded units and installed unit
+// files matching a shell-style pattern such as "nginx@*.service", sorted
+// by name. Template unit files (foo@.service) are left out, as they can't
+// be started or stopped themselves.
+//
+// A pattern without any of the special characters "*", "?" or "[" is
+// returned unchanged, like systemctl treats it as a plain unit name.
+func ExpandUnits(ctx context.Context, pattern string, opts Options) ([]string, error) {
+	if !isGlob(pattern) {
+		return []string{pattern}, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("%q: %w", pattern, ErrInvalidName)
+	}
+	opts = opts.withoutFlags()
+	loaded, err := ListUnits(ctx, ListUnitsOptions{All: true, Patterns: []string{pattern}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var units []string
+	for _, unit := range loaded {
+		units = append(units, unit.Name)
+	}
+	files, err := ListUnitFiles(ctx, ListUnitFilesOptions{Patterns: []string{pattern}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if !strings.Contains(file.Name, "@.") {
+			units = append(units, file.Name)
+		}
+	}
+	// Filter locally as well, in case systemctl ignored the pattern.
+	units = slices.DeleteFunc(units, func(unit string) bool {
+		matched, _ := path.Match(pattern, unit)
+		return !matched
+	})
+	slices.Sort(units)
+	return slices.Compact(units), nil
+}
+
+// StopMatching stops every unit matched by the pattern (see ExpandUnits)
+// and reports the result for each unit separately. The returned error
+// joins the errors of all units which failed to stop.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func StopMatching(ctx context.Context, pattern string, opts Options, args ...string) ([]UnitResult, error) {
+	return forEachMatching(ctx, pattern, opts, func(unit string) error {
+		return Stop(ctx, unit, opts, args...)
+	})
+}
+
+// RestartMatching restarts every unit matched by the pattern (see
+// ExpandUnits) and reports the result for each unit separately. The
+// returned error joins the errors of all units which failed to restart.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func RestartMatching(ctx context.Context, pattern string, opts Options, args ...string) ([]UnitResult, error) {
+	return forEachMatching(ctx, pattern, opts, func(unit string) error {
+		return Restart(ctx, unit, opts, args...)
+	})
+}
+
+// IsActiveMatching reports, for every unit matched by the pattern (see
+// ExpandUnits), whether it is active.
+func IsActiveMatching(ctx context.Context, pattern string, opts Options) (map[string]bool, error) {
+	units, err := ExpandUnits(ctx, pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+	states, err := GetActiveStates(ctx, units, opts.withoutFlags())
+	if err != nil {
+		return nil, err
+	}
+	active := make(map[string]bool, len(states))
+	for unit, state := range states {
+		active[unit] = state == "active"
+	}
+	return active, nil
+}
+
+func forEachMatching(ctx context.Context, pattern string, opts Options, action func(string) error) ([]UnitResult, error) {
+	units, err := ExpandUnits(ctx, pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]UnitResult, len(units))
+	var errs []error
+	for i, unit := range units {
+		results[i] = UnitResult{Unit: unit, Err: action(unit)}
+		if results[i].Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", unit, results[i].Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
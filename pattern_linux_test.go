@@ -0,0 +1,99 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// patternSystemctl fakes two loaded nginx instances, one of them failed,
+// and a third instance which is only enabled. Stopping nginx@b fails.
+const patternSystemctl = `case "$1" in
+list-units)
+	printf '  UNIT            LOAD   ACTIVE SUB     DESCRIPTION\n'
+	printf '  nginx@a.service loaded active running Web server a\n'
+	printf '● nginx@b.service loaded failed failed  Web server b\n' ;;
+list-unit-files)
+	printf 'nginx@.service  disabled enabled\n'
+	printf 'nginx@c.service enabled  enabled\n'
+	printf 'nginx@a.service enabled  enabled\n' ;;
+is-active)
+	shift 2
+	for unit in "$@"; do
+		if [ "$unit" = nginx@a.service ]; then echo active; else echo inactive; fi
+	done
+	exit 3 ;;
+stop)
+	if [ "$3" = nginx@b.service ]; then
+		echo "Failed to stop nginx@b.service: Access denied" >&2
+		exit 1
+	fi ;;
+esac`
+
+func TestExpandUnits(t *testing.T) {
+	logFile := fakeSystemctl(t, patternSystemctl)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	units, err := ExpandUnits(ctx, "nginx@*.service", Options{})
+	if err != nil {
+		t.Fatalf("ExpandUnits returned error: %v", err)
+	}
+	want := []string{"nginx@a.service", "nginx@b.service", "nginx@c.service"}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("ExpandUnits = %v, want %v", units, want)
+	}
+	wantArgs := [][]string{
+		{"list-units", "--system", "nginx@*.service", "--full", "--no-pager", "--output=json", "--all"},
+		{"list-unit-files", "--system", "nginx@*.service", "--no-legend", "--full", "--no-pager"},
+	}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+
+	units, err = ExpandUnits(ctx, "nginx.service", Options{})
+	if err != nil || !reflect.DeepEqual(units, []string{"nginx.service"}) {
+		t.Errorf("ExpandUnits(plain name) = %v, %v", units, err)
+	}
+	if _, err := ExpandUnits(ctx, "nginx@[.service", Options{}); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("error is %v, but should have been %v", err, ErrInvalidName)
+	}
+}
+
+func TestStopMatching(t *testing.T) {
+	fakeSystemctl(t, patternSystemctl)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	results, err := StopMatching(ctx, "nginx@*", Options{})
+	if !errors.Is(err, ErrInsufficientPermissions) {
+		t.Errorf("error is %v, but should have been %v", err, ErrInsufficientPermissions)
+	}
+	if len(results) != 3 {
+		t.Fatalf("results = %+v, want 3 units", results)
+	}
+	for _, r := range results {
+		if failed := r.Err != nil; failed != (r.Unit == "nginx@b.service") {
+			t.Errorf("%s: unexpected result %v", r.Unit, r.Err)
+		}
+	}
+}
+
+func TestIsActiveMatching(t *testing.T) {
+	fakeSystemctl(t, patternSystemctl)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	got, err := IsActiveMatching(ctx, "nginx@*.service", Options{})
+	if err != nil {
+		t.Fatalf("IsActiveMatching returned error: %v", err)
+	}
+	want := map[string]bool{"nginx@a.service": true, "nginx@b.service": false, "nginx@c.service": false}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("IsActiveMatching = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPresetReportsChanges(t *testing.T) {
+	logFile := fakeSystemctl(t, `echo "Removed /etc/systemd/system/multi-user.target.wants/foo.service." >&2`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	changes, err := Preset(ctx, "foo.service", PresetDisableOnly, Options{})
+	if err != nil {
+		t.Fatalf("Preset returned error: %v", err)
+	}
+	want := ChangeSet{
+		Removed: []string{"/etc/systemd/system/multi-user.target.wants/foo.service"},
+		Changed: true,
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Preset changes = %+v, want %+v", changes, want)
+	}
+	wantArgs := [][]string{{"preset", "--system", "foo.service", "--preset-mode=disable-only"}}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
+
+func TestPresetAllDefaultMode(t *testing.T) {
+	logFile := fakeSystemctl(t, ``)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	changes, err := PresetAll(ctx, "", Options{UserMode: true})
+	if err != nil {
+		t.Fatalf("PresetAll returned error: %v", err)
+	}
+	if changes.Changed {
+		t.Errorf("PresetAll reported changes without output: %+v", changes)
+	}
+	wantArgs := [][]string{{"preset-all", "--user"}}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
@@ -17,6 +17,7 @@ const (
 	Before                               Property = "Before"
 	BindIPv6Only                         Property = "BindIPv6Only"
 	BindLogSockets                       Property = "BindLogSockets"
+	BindsTo                              Property = "BindsTo"
 	BlockIOAccounting                    Property = "BlockIOAccounting"
 	BlockIOWeight                        Property = "BlockIOWeight"
 	Broadcast                            Property = "Broadcast"
@@ -60,6 +61,7 @@ const (
 	Description                          Property = "Description"
 	DevicePolicy                         Property = "DevicePolicy"
 	DirectoryMode                        Property = "DirectoryMode"
+	DropInPaths                          Property = "DropInPaths"
 	DynamicUser                          Property = "DynamicUser"
 	EffectiveCPUs                        Property = "EffectiveCPUs"
 	EffectiveMemoryHigh                  Property = "EffectiveMemoryHigh"
@@ -78,9 +80,11 @@ const (
 	ExecStartEx                          Property = "ExecStartEx"
 	ExtensionImagePolicy                 Property = "ExtensionImagePolicy"
 	FailureAction                        Property = "FailureAction"
+	Features                             Property = "Features"
 	FileDescriptorName                   Property = "FileDescriptorName"
 	FileDescriptorStoreMax               Property = "FileDescriptorStoreMax"
 	FinalKillSignal                      Property = "FinalKillSignal"
+	FinishTimestamp                      Property = "FinishTimestamp"
 	FlushPending                         Property = "FlushPending"
 	FragmentPath                         Property = "FragmentPath"
 	FreeBind                             Property = "FreeBind"
@@ -109,6 +113,7 @@ const (
 	InactiveExitTimestamp                Property = "InactiveExitTimestamp"
 	InactiveExitTimestampMonotonic       Property = "InactiveExitTimestampMonotonic"
 	InvocationID                         Property = "InvocationID"
+	Job                                  Property = "Job"
 	JobRunningTimeoutUSec                Property = "JobRunningTimeoutUSec"
 	JobTimeoutAction                     Property = "JobTimeoutAction"
 	JobTimeoutUSec                       Property = "JobTimeoutUSec"
@@ -119,6 +124,8 @@ const (
 	KeyringMode                          Property = "KeyringMode"
 	KillMode                             Property = "KillMode"
 	KillSignal                           Property = "KillSignal"
+	LastTriggerUSec                      Property = "LastTriggerUSec"
+	LastTriggerUSecMonotonic             Property = "LastTriggerUSecMonotonic"
 	LimitAS                              Property = "LimitAS"
 	LimitASSoft                          Property = "LimitASSoft"
 	LimitCORE                            Property = "LimitCORE"
@@ -192,12 +199,16 @@ const (
 	MountImagePolicy                     Property = "MountImagePolicy"
 	NAccepted                            Property = "NAccepted"
 	NConnections                         Property = "NConnections"
+	NFailedUnits                         Property = "NFailedUnits"
 	NFileDescriptorStore                 Property = "NFileDescriptorStore"
+	NJobs                                Property = "NJobs"
 	NRefused                             Property = "NRefused"
 	NRestarts                            Property = "NRestarts"
 	NUMAPolicy                           Property = "NUMAPolicy"
 	Names                                Property = "Names"
 	NeedDaemonReload                     Property = "NeedDaemonReload"
+	NextElapseUSecMonotonic              Property = "NextElapseUSecMonotonic"
+	NextElapseUSecRealtime               Property = "NextElapseUSecRealtime"
 	Nice                                 Property = "Nice"
 	NoDelay                              Property = "NoDelay"
 	NoNewPrivileges                      Property = "NoNewPrivileges"
@@ -208,6 +219,7 @@ const (
 	OnFailureJobMode                     Property = "OnFailureJobMode"
 	OnSuccessJobMode                     Property = "OnSuccessJobMode"
 	PIDFile                              Property = "PIDFile"
+	PartOf                               Property = "PartOf"
 	PassCredentials                      Property = "PassCredentials"
 	PassFileDescriptorsToExec            Property = "PassFileDescriptorsToExec"
 	PassPacketInfo                       Property = "PassPacketInfo"
@@ -323,8 +335,12 @@ const (
 	UMask                                Property = "UMask"
 	UnitFilePreset                       Property = "UnitFilePreset"
 	UnitFileState                        Property = "UnitFileState"
+	UserspaceTimestamp                   Property = "UserspaceTimestamp"
 	UtmpMode                             Property = "UtmpMode"
+	Version                              Property = "Version"
+	Virtualization                       Property = "Virtualization"
 	WantedBy                             Property = "WantedBy"
+	Wants                                Property = "Wants"
 	WatchdogSignal                       Property = "WatchdogSignal"
 	WatchdogTimestampMonotonic           Property = "WatchdogTimestampMonotonic"
 	WatchdogUSec                         Property = "WatchdogUSec"
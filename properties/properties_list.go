@@ -15,6 +15,7 @@ var Properties = []Property{
 	Before,
 	BindIPv6Only,
 	BindLogSockets,
+	BindsTo,
 	BlockIOAccounting,
 	BlockIOWeight,
 	Broadcast,
@@ -58,6 +59,7 @@ var Properties = []Property{
 	Description,
 	DevicePolicy,
 	DirectoryMode,
+	DropInPaths,
 	DynamicUser,
 	EffectiveCPUs,
 	EffectiveMemoryHigh,
@@ -76,9 +78,11 @@ var Properties = []Property{
 	ExecStartEx,
 	ExtensionImagePolicy,
 	FailureAction,
+	Features,
 	FileDescriptorName,
 	FileDescriptorStoreMax,
 	FinalKillSignal,
+	FinishTimestamp,
 	FlushPending,
 	FragmentPath,
 	FreeBind,
@@ -107,6 +111,7 @@ var Properties = []Property{
 	InactiveExitTimestamp,
 	InactiveExitTimestampMonotonic,
 	InvocationID,
+	Job,
 	JobRunningTimeoutUSec,
 	JobTimeoutAction,
 	JobTimeoutUSec,
@@ -117,6 +122,8 @@ var Properties = []Property{
 	KeyringMode,
 	KillMode,
 	KillSignal,
+	LastTriggerUSec,
+	LastTriggerUSecMonotonic,
 	LimitAS,
 	LimitASSoft,
 	LimitCORE,
@@ -190,12 +197,16 @@ var Properties = []Property{
 	MountImagePolicy,
 	NAccepted,
 	NConnections,
+	NFailedUnits,
 	NFileDescriptorStore,
+	NJobs,
 	NRefused,
 	NRestarts,
 	NUMAPolicy,
 	Names,
 	NeedDaemonReload,
+	NextElapseUSecMonotonic,
+	NextElapseUSecRealtime,
 	Nice,
 	NoDelay,
 	NoNewPrivileges,
@@ -206,6 +217,7 @@ var Properties = []Property{
 	OnFailureJobMode,
 	OnSuccessJobMode,
 	PIDFile,
+	PartOf,
 	PassCredentials,
 	PassFileDescriptorsToExec,
 	PassPacketInfo,
@@ -321,8 +333,12 @@ var Properties = []Property{
 	UMask,
 	UnitFilePreset,
 	UnitFileState,
+	UserspaceTimestamp,
 	UtmpMode,
+	Version,
+	Virtualization,
 	WantedBy,
+	Wants,
 	WatchdogSignal,
 	WatchdogTimestampMonotonic,
 	WatchdogUSec,
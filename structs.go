@@ -4,16 +4,139 @@ import "strings"
 
 type Options struct {
 	UserMode bool
+	// User names the account whose user manager should be targeted when
+	// UserMode is set. This allows a process running as root to manage
+	// another user's services via `--machine=<user>@.host`, which requires
+	// systemd 248 or newer; older versions return ErrUnsupportedBySystemd.
+	// Leave empty to target the caller's own manager.
+	User string
+	// Strict rejects additional arguments which are not known systemctl
+	// flags with ErrFlagNotAllowed, for callers which pass on untrusted
+	// input. Flags taking a value must be given as "--flag=value".
+	Strict bool
+	// Flags are typed systemctl flags such as WithNow() or WithNoBlock(),
+	// checked against the command before it is run.
+	Flags []Flag
 }
 
 type Unit struct {
-	Name        string
-	Load        string
-	Active      string
-	Sub         string
+	Name   string
+	Load   string
+	Active string
+	Sub    string
+	// Job is the type of the job queued for the unit ("start", "stop",
+	// ...), or empty if there is none.
+	Job         string
 	Description string
 }
 
+// ListUnitsOptions filters the units returned by ListUnits.
+type ListUnitsOptions struct {
+	// Types restricts the list to the given unit types, e.g. "service".
+	Types []string
+	// States restricts the list to units in any of the given load, active
+	// or sub states, e.g. "failed" or "running".
+	States []string
+	// Patterns restricts the list to units matching any of the given
+	// shell-style patterns, e.g. "nginx@*".
+	Patterns []string
+	// Failed lists only failed units. It is a shorthand for
+	// States: []string{"failed"}.
+	Failed bool
+	// All includes inactive units and units which are not loaded.
+	All bool
+}
+
+// UnitFile is an installed unit file as listed by `systemctl list-unit-files`.
+type UnitFile struct {
+	// Name is the full unit file name, including its type suffix.
+	Name  string
+	State UnitFileState
+	// Preset is the vendor preset of the unit file ("enabled" or
+	// "disabled"), or empty if no preset applies or systemd is too old to
+	// report it.
+	Preset string
+}
+
+// UnitFileState is the enablement state of a unit file, as reported by
+// `systemctl list-unit-files` and `systemctl is-enabled`.
+type UnitFileState string
+
+const (
+	UnitFileEnabled        UnitFileState = "enabled"
+	UnitFileEnabledRuntime UnitFileState = "enabled-runtime"
+	UnitFileLinked         UnitFileState = "linked"
+	UnitFileLinkedRuntime  UnitFileState = "linked-runtime"
+	UnitFileAlias          UnitFileState = "alias"
+	UnitFileMasked         UnitFileState = "masked"
+	UnitFileMaskedRuntime  UnitFileState = "masked-runtime"
+	UnitFileStatic         UnitFileState = "static"
+	UnitFileIndirect       UnitFileState = "indirect"
+	UnitFileDisabled       UnitFileState = "disabled"
+	UnitFileGenerated      UnitFileState = "generated"
+	UnitFileTransient      UnitFileState = "transient"
+	UnitFileBad            UnitFileState = "bad"
+)
+
+// ListUnitFilesOptions filters the unit files returned by ListUnitFiles.
+type ListUnitFilesOptions struct {
+	// States restricts the list to unit files in any of the given states.
+	States []UnitFileState
+	// Types restricts the list to the given unit types, e.g. "service".
+	Types []string
+	// Patterns restricts the list to unit files matching any of the given
+	// shell-style patterns, e.g. "nginx@*".
+	Patterns []string
+}
+
+// PresetMode selects which changes Preset and PresetAll may apply.
+type PresetMode string
+
+const (
+	// PresetFull enables and disables units according to the preset policy.
+	PresetFull PresetMode = "full"
+	// PresetEnableOnly only enables units according to the preset policy.
+	PresetEnableOnly PresetMode = "enable-only"
+	// PresetDisableOnly only disables units according to the preset policy.
+	PresetDisableOnly PresetMode = "disable-only"
+)
+
+// SystemState is the overall state of the service manager, as reported by
+// `systemctl is-system-running`.
+type SystemState string
+
+const (
+	// SystemInitializing is the early boot, before basic.target is reached.
+	SystemInitializing SystemState = "initializing"
+	// SystemStarting is the late boot, before the job queue is idle for the
+	// first time.
+	SystemStarting SystemState = "starting"
+	// SystemRunning means the system is fully operational.
+	SystemRunning SystemState = "running"
+	// SystemDegraded means the system is operational, but units failed.
+	SystemDegraded SystemState = "degraded"
+	// SystemMaintenance means the rescue or emergency target is active.
+	SystemMaintenance SystemState = "maintenance"
+	// SystemStopping means the manager is shutting down.
+	SystemStopping SystemState = "stopping"
+	// SystemOffline means the manager is not running, or is not PID 1 for
+	// the system manager.
+	SystemOffline SystemState = "offline"
+	// SystemUnknown means the state could not be determined.
+	SystemUnknown SystemState = "unknown"
+)
+
+var systemStates = []SystemState{
+	SystemInitializing,
+	SystemStarting,
+	SystemRunning,
+	SystemDegraded,
+	SystemMaintenance,
+	SystemStopping,
+	SystemOffline,
+	SystemUnknown,
+}
+
 // UnitTypes contains all valid systemd unit type suffixes.
 var UnitTypes = []string{
 	"automount",
@@ -6,6 +6,17 @@ import (
 	"github.com/taigrr/systemctl/properties"
 )
 
+// Show the backing files of a unit, as returned by `systemctl cat [unit]`.
+// This prints the "fragment" and "drop-ins" (source files) of the unit,
+// each preceded by a comment which includes the file name.
+//
+// Use unitfile.ParseCat or ReadUnit to parse the output.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func Cat(ctx context.Context, unit string, opts Options, args ...string) (string, error) {
+	return cat(ctx, unit, opts, args...)
+}
+
 // Reload systemd manager configuration.
 //
 // This will rerun all generators (see systemd. generator(7)), reload all unit
@@ -18,6 +29,100 @@ func DaemonReload(ctx context.Context, opts Options, args ...string) error {
 	return daemonReload(ctx, opts, args...)
 }
 
+// Add a "Wants=" dependency on one or more units to the specified target,
+// by creating symlinks in the target's .wants/ directory. If the target has
+// no unit suffix, ".target" is assumed.
+//
+// The returned ChangeSet lists the symlinks created. See ChangeSet for how
+// --quiet is handled.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func AddWants(ctx context.Context, target string, unit string, opts Options, args ...string) (ChangeSet, error) {
+	return addDependency(ctx, "add-wants", target, unit, opts, withoutQuiet(args)...)
+}
+
+// Add a "Requires=" dependency on one or more units to the specified target,
+// by creating symlinks in the target's .requires/ directory. If the target
+// has no unit suffix, ".target" is assumed.
+//
+// The returned ChangeSet lists the symlinks created. See ChangeSet for how
+// --quiet is handled.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func AddRequires(ctx context.Context, target string, unit string, opts Options, args ...string) (ChangeSet, error) {
+	return addDependency(ctx, "add-requires", target, unit, opts, withoutQuiet(args)...)
+}
+
+// Return the default target to boot into, as returned by
+// `systemctl get-default`. This is the unit default.target is aliased to.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func GetDefault(ctx context.Context, opts Options, args ...string) (string, error) {
+	return getDefault(ctx, opts, args...)
+}
+
+// Start the unit specified on the command line and its dependencies and stop
+// all others, unless they have IgnoreOnIsolate=yes.
+//
+// If no unit suffix is given, ".target" is assumed. Before attempting the
+// switch, the unit's CanIsolate property is checked and ErrIsolateNotAllowed
+// is returned if the unit refuses isolation (see AllowIsolate in
+// systemd.unit(5)).
+//
+// Any additional arguments are passed directly to the systemctl command.
+func Isolate(ctx context.Context, unit string, opts Options, args ...string) error {
+	return isolate(ctx, unit, opts, args...)
+}
+
+// Enter emergency mode. This is mostly equivalent to isolating
+// emergency.target, but also prints a wall message to all users.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func Emergency(ctx context.Context, opts Options, args ...string) error {
+	return emergency(ctx, opts, args...)
+}
+
+// Enter rescue mode. This is mostly equivalent to isolating rescue.target,
+// but also prints a wall message to all users.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func Rescue(ctx context.Context, opts Options, args ...string) error {
+	return rescue(ctx, opts, args...)
+}
+
+// Enter default mode. This is equivalent to isolating default.target.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func Default(ctx context.Context, opts Options, args ...string) error {
+	return defaultMode(ctx, opts, args...)
+}
+
+// Reset the enable/disable status of one or more unit files to the defaults
+// specified in the preset policy files (see systemd.preset(5)).
+//
+// The mode controls whether units may be enabled and disabled (PresetFull),
+// only enabled (PresetEnableOnly) or only disabled (PresetDisableOnly).
+// An empty mode uses the systemctl default, which is PresetFull.
+//
+// The returned ChangeSet lists the symlinks created and removed. See
+// ChangeSet for how --quiet is handled.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func Preset(ctx context.Context, unit string, mode PresetMode, opts Options, args ...string) (ChangeSet, error) {
+	return preset(ctx, unit, mode, opts, withoutQuiet(args)...)
+}
+
+// Reset all installed unit files to the defaults configured in the preset
+// policy files (see systemd.preset(5)). See Preset for the meaning of mode.
+//
+// The returned ChangeSet lists the symlinks created and removed. See
+// ChangeSet for how --quiet is handled.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func PresetAll(ctx context.Context, mode PresetMode, opts Options, args ...string) (ChangeSet, error) {
+	return presetAll(ctx, mode, opts, withoutQuiet(args)...)
+}
+
 // Reenables one or more units.
 //
 // This removes all symlinks to the unit files backing the specified units from
@@ -26,7 +131,14 @@ func DaemonReload(ctx context.Context, opts Options, args ...string) error {
 //
 // Any additional arguments are passed directly to the systemctl command.
 func Reenable(ctx context.Context, unit string, opts Options, args ...string) error {
-	return reenable(ctx, unit, opts, args...)
+	_, err := reenable(ctx, unit, opts, args...)
+	return err
+}
+
+// ReenableWithChanges behaves like Reenable, but also returns the symlinks created
+// and removed. See ChangeSet for how --quiet is handled.
+func ReenableWithChanges(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error) {
+	return reenable(ctx, unit, opts, withoutQuiet(args)...)
 }
 
 // Disables one or more units.
@@ -37,7 +149,14 @@ func Reenable(ctx context.Context, unit string, opts Options, args ...string) er
 //
 // Any additional arguments are passed directly to the systemctl command.
 func Disable(ctx context.Context, unit string, opts Options, args ...string) error {
-	return disable(ctx, unit, opts, args...)
+	_, err := disable(ctx, unit, opts, args...)
+	return err
+}
+
+// DisableWithChanges behaves like Disable, but also returns the symlinks created
+// and removed. See ChangeSet for how --quiet is handled.
+func DisableWithChanges(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error) {
+	return disable(ctx, unit, opts, withoutQuiet(args)...)
 }
 
 // Enable one or more units or unit instances.
@@ -49,7 +168,14 @@ func Disable(ctx context.Context, unit string, opts Options, args ...string) err
 //
 // Any additional arguments are passed directly to the systemctl command.
 func Enable(ctx context.Context, unit string, opts Options, args ...string) error {
-	return enable(ctx, unit, opts, args...)
+	_, err := enable(ctx, unit, opts, args...)
+	return err
+}
+
+// EnableWithChanges behaves like Enable, but also returns the symlinks created
+// and removed. See ChangeSet for how --quiet is handled.
+func EnableWithChanges(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error) {
+	return enable(ctx, unit, opts, withoutQuiet(args)...)
 }
 
 // Check whether any of the specified units are active (i.e. running).
@@ -84,6 +210,56 @@ func IsFailed(ctx context.Context, unit string, opts Options, args ...string) (b
 	return isFailed(ctx, unit, opts, args...)
 }
 
+// Send a UNIX process signal to one or more processes of the unit. The
+// signal defaults to SIGTERM; use WithSignal in Options.Flags to choose
+// another one.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func Kill(ctx context.Context, unit string, opts Options, args ...string) error {
+	return kill(ctx, unit, opts, args...)
+}
+
+// Check whether the system is operational, and return its state: one of
+// initializing, starting, running, degraded, maintenance, stopping or
+// offline, or unknown if it can't be determined. Unlike systemctl, a state
+// other than running is not reported as an error.
+//
+// Use WithWait in Options.Flags to wait until the boot process has
+// completed before returning.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func IsSystemRunning(ctx context.Context, opts Options, args ...string) (SystemState, error) {
+	return isSystemRunning(ctx, opts, args...)
+}
+
+// Link a unit file that is not in the unit file search path into the unit
+// file search path. The path must be absolute; relative paths are resolved
+// against the current working directory.
+//
+// A linked unit file can be started and enabled like any other unit, but
+// IsEnabled will report ErrLinked for it until it is enabled.
+//
+// The returned ChangeSet lists the symlinks created. See ChangeSet for how
+// --quiet is handled.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func Link(ctx context.Context, path string, opts Options, args ...string) (ChangeSet, error) {
+	return link(ctx, path, opts, withoutQuiet(args)...)
+}
+
+// List the units the given unit depends on, as a tree, as returned by
+// `systemctl list-dependencies [unit]`. The root of the returned tree is the
+// unit itself.
+//
+// By default only requirement dependencies (Requires=, Wants=, ...) are
+// shown and only target units are expanded recursively; see
+// DependencyOptions for reverse and ordering dependencies.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func ListDependencies(ctx context.Context, unit string, dopts DependencyOptions, opts Options, args ...string) (*Dependency, error) {
+	return listDependencies(ctx, unit, dopts, opts, args...)
+}
+
 // Mask one or more units, as specified on the command line. This will link
 // these unit files to /dev/null, making it impossible to start them.
 //
@@ -93,7 +269,14 @@ func IsFailed(ctx context.Context, unit string, opts Options, args ...string) (b
 //
 // Any additional arguments are passed directly to the systemctl command.
 func Mask(ctx context.Context, unit string, opts Options, args ...string) error {
-	return mask(ctx, unit, opts, args...)
+	_, err := mask(ctx, unit, opts, args...)
+	return err
+}
+
+// MaskWithChanges behaves like Mask, but also returns the symlinks created
+// and removed. See ChangeSet for how --quiet is handled.
+func MaskWithChanges(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error) {
+	return mask(ctx, unit, opts, withoutQuiet(args)...)
 }
 
 // Stop and then start one or more units specified on the command line.
@@ -111,6 +294,39 @@ func Reload(ctx context.Context, unit string, opts Options, args ...string) erro
 	return reload(ctx, unit, opts, args...)
 }
 
+// Set the default target to boot into. This sets (aliases) the
+// default.target unit to the given target unit.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func SetDefault(ctx context.Context, target string, opts Options, args ...string) error {
+	return setDefault(ctx, target, opts, args...)
+}
+
+// Revert one or more unit files to their vendor versions. This removes
+// drop-in configuration files that modify the unit, as well as any
+// user-configured unit file that overrides a vendor-supplied one, and
+// unmasks the unit if it was masked.
+//
+// The returned ChangeSet lists the files and symlinks removed. See
+// ChangeSet for how --quiet is handled.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func Revert(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error) {
+	return revert(ctx, unit, opts, withoutQuiet(args)...)
+}
+
+// Reset the "failed" state of the specified unit, or, if no unit name is
+// passed, reset the state of all units. When a unit fails in some way (i.e.
+// process exiting with non-zero error code, terminating abnormally or timing
+// out), it will automatically enter the "failed" state and its exit code and
+// status is recorded for introspection by the administrator until the
+// service is stopped/re-started or reset with this command.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func ResetFailed(ctx context.Context, unit string, opts Options, args ...string) error {
+	return resetFailed(ctx, unit, opts, args...)
+}
+
 // Show a selected property of a unit. Accepted properties are predefined in the
 // properties subpackage to guarantee properties are valid and assist code-completion.
 //
@@ -154,5 +370,12 @@ func Stop(ctx context.Context, unit string, opts Options, args ...string) error
 //
 // Any additional arguments are passed directly to the systemctl command.
 func Unmask(ctx context.Context, unit string, opts Options, args ...string) error {
-	return unmask(ctx, unit, opts, args...)
+	_, err := unmask(ctx, unit, opts, args...)
+	return err
+}
+
+// UnmaskWithChanges behaves like Unmask, but also returns the symlinks created
+// and removed. See ChangeSet for how --quiet is handled.
+func UnmaskWithChanges(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error) {
+	return unmask(ctx, unit, opts, withoutQuiet(args)...)
 }
@@ -8,22 +8,58 @@ import (
 	"github.com/taigrr/systemctl/properties"
 )
 
+func addDependency(_ context.Context, _ string, _ string, _ string, _ Options, _ ...string) (ChangeSet, error) {
+	return ChangeSet{}, nil
+}
+
+func cat(_ context.Context, _ string, _ Options, _ ...string) (string, error) {
+	return "", nil
+}
+
 func daemonReload(_ context.Context, _ Options, _ ...string) error {
 	return nil
 }
 
-func reenable(_ context.Context, _ string, _ Options, _ ...string) error {
+func getDefault(_ context.Context, _ Options, _ ...string) (string, error) {
+	return "", nil
+}
+
+func isolate(_ context.Context, _ string, _ Options, _ ...string) error {
 	return nil
 }
 
-func disable(_ context.Context, _ string, _ Options, _ ...string) error {
+func emergency(_ context.Context, _ Options, _ ...string) error {
 	return nil
 }
 
-func enable(_ context.Context, _ string, _ Options, _ ...string) error {
+func rescue(_ context.Context, _ Options, _ ...string) error {
 	return nil
 }
 
+func defaultMode(_ context.Context, _ Options, _ ...string) error {
+	return nil
+}
+
+func preset(_ context.Context, _ string, _ PresetMode, _ Options, _ ...string) (ChangeSet, error) {
+	return ChangeSet{}, nil
+}
+
+func presetAll(_ context.Context, _ PresetMode, _ Options, _ ...string) (ChangeSet, error) {
+	return ChangeSet{}, nil
+}
+
+func reenable(_ context.Context, _ string, _ Options, _ ...string) (ChangeSet, error) {
+	return ChangeSet{}, nil
+}
+
+func disable(_ context.Context, _ string, _ Options, _ ...string) (ChangeSet, error) {
+	return ChangeSet{}, nil
+}
+
+func enable(_ context.Context, _ string, _ Options, _ ...string) (ChangeSet, error) {
+	return ChangeSet{}, nil
+}
+
 func isActive(_ context.Context, _ string, _ Options, _ ...string) (bool, error) {
 	return false, nil
 }
@@ -36,7 +72,27 @@ func isFailed(_ context.Context, _ string, _ Options, _ ...string) (bool, error)
 	return false, nil
 }
 
-func mask(_ context.Context, _ string, _ Options, _ ...string) error {
+func isSystemRunning(_ context.Context, _ Options, _ ...string) (SystemState, error) {
+	return SystemUnknown, nil
+}
+
+func kill(_ context.Context, _ string, _ Options, _ ...string) error {
+	return nil
+}
+
+func link(_ context.Context, _ string, _ Options, _ ...string) (ChangeSet, error) {
+	return ChangeSet{}, nil
+}
+
+func listDependencies(_ context.Context, _ string, _ DependencyOptions, _ Options, _ ...string) (*Dependency, error) {
+	return nil, nil
+}
+
+func mask(_ context.Context, _ string, _ Options, _ ...string) (ChangeSet, error) {
+	return ChangeSet{}, nil
+}
+
+func resetFailed(_ context.Context, _ string, _ Options, _ ...string) error {
 	return nil
 }
 
@@ -48,6 +104,14 @@ func reload(_ context.Context, _ string, _ Options, _ ...string) error {
 	return nil
 }
 
+func revert(_ context.Context, _ string, _ Options, _ ...string) (ChangeSet, error) {
+	return ChangeSet{}, nil
+}
+
+func setDefault(_ context.Context, _ string, _ Options, _ ...string) error {
+	return nil
+}
+
 func show(_ context.Context, _ string, _ properties.Property, _ Options, _ ...string) (string, error) {
 	return "", nil
 }
@@ -64,6 +128,6 @@ func stop(_ context.Context, _ string, _ Options, _ ...string) error {
 	return nil
 }
 
-func unmask(_ context.Context, _ string, _ Options, _ ...string) error {
-	return nil
+func unmask(_ context.Context, _ string, _ Options, _ ...string) (ChangeSet, error) {
+	return ChangeSet{}, nil
 }
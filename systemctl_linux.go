@@ -4,37 +4,159 @@ package systemctl
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/taigrr/systemctl/properties"
 )
 
+func addDependency(ctx context.Context, verb string, target string, unit string, opts Options, args ...string) (ChangeSet, error) {
+	a, err := prepareUnitArgs(verb, opts, []string{targetUnitName(target), unit}, nil, args)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	return parseChangeSet(stdout, stderr), err
+}
+
+func cat(ctx context.Context, unit string, opts Options, args ...string) (string, error) {
+	a, err := prepareUnitArgs("cat", opts, []string{unit}, nil, args)
+	if err != nil {
+		return "", err
+	}
+	stdout, _, _, err := execute(ctx, a)
+	return stdout, err
+}
+
 func daemonReload(ctx context.Context, opts Options, args ...string) error {
-	a := prepareArgs("daemon-reload", opts, args...)
-	_, _, _, err := execute(ctx, a)
+	a, err := prepareUnitArgs("daemon-reload", opts, nil, nil, args)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = execute(ctx, a)
 	return err
 }
 
-func reenable(ctx context.Context, unit string, opts Options, args ...string) error {
-	a := prepareArgs("reenable", opts, append([]string{unit}, args...)...)
-	_, _, _, err := execute(ctx, a)
+func getDefault(ctx context.Context, opts Options, args ...string) (string, error) {
+	a, err := prepareUnitArgs("get-default", opts, nil, nil, args)
+	if err != nil {
+		return "", err
+	}
+	stdout, _, _, err := execute(ctx, a)
+	return strings.TrimSuffix(stdout, "\n"), err
+}
+
+func isolate(ctx context.Context, unit string, opts Options, args ...string) error {
+	unit = targetUnitName(unit)
+	loadState, err := show(ctx, unit, properties.LoadState, opts.withoutFlags())
+	if err != nil {
+		return err
+	}
+	if loadState == "not-found" {
+		return ErrDoesNotExist
+	}
+	canIsolate, err := show(ctx, unit, properties.CanIsolate, opts.withoutFlags())
+	if err != nil {
+		return err
+	}
+	if canIsolate != "yes" {
+		return fmt.Errorf("%s: %w", unit, ErrIsolateNotAllowed)
+	}
+	a, err := prepareUnitArgs("isolate", opts, []string{unit}, nil, args)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = execute(ctx, a)
 	return err
 }
 
-func disable(ctx context.Context, unit string, opts Options, args ...string) error {
-	a := prepareArgs("disable", opts, append([]string{unit}, args...)...)
-	_, _, _, err := execute(ctx, a)
+func emergency(ctx context.Context, opts Options, args ...string) error {
+	a, err := prepareUnitArgs("emergency", opts, nil, nil, args)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = execute(ctx, a)
+	return err
+}
+
+func rescue(ctx context.Context, opts Options, args ...string) error {
+	a, err := prepareUnitArgs("rescue", opts, nil, nil, args)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = execute(ctx, a)
 	return err
 }
 
-func enable(ctx context.Context, unit string, opts Options, args ...string) error {
-	a := prepareArgs("enable", opts, append([]string{unit}, args...)...)
-	_, _, _, err := execute(ctx, a)
+func defaultMode(ctx context.Context, opts Options, args ...string) error {
+	a, err := prepareUnitArgs("default", opts, nil, nil, args)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = execute(ctx, a)
 	return err
 }
 
+func preset(ctx context.Context, unit string, mode PresetMode, opts Options, args ...string) (ChangeSet, error) {
+	var flags []string
+	if mode != "" {
+		flags = append(flags, "--preset-mode="+string(mode))
+	}
+	a, err := prepareUnitArgs("preset", opts, []string{unit}, flags, args)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	return parseChangeSet(stdout, stderr), err
+}
+
+func presetAll(ctx context.Context, mode PresetMode, opts Options, args ...string) (ChangeSet, error) {
+	var flags []string
+	if mode != "" {
+		flags = append(flags, "--preset-mode="+string(mode))
+	}
+	a, err := prepareUnitArgs("preset-all", opts, nil, flags, args)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	return parseChangeSet(stdout, stderr), err
+}
+
+func reenable(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error) {
+	a, err := prepareUnitArgs("reenable", opts, []string{unit}, nil, args)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	return parseChangeSet(stdout, stderr), err
+}
+
+func disable(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error) {
+	a, err := prepareUnitArgs("disable", opts, []string{unit}, nil, args)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	return parseChangeSet(stdout, stderr), err
+}
+
+func enable(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error) {
+	a, err := prepareUnitArgs("enable", opts, []string{unit}, nil, args)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	return parseChangeSet(stdout, stderr), err
+}
+
 func isActive(ctx context.Context, unit string, opts Options, args ...string) (bool, error) {
-	a := prepareArgs("is-active", opts, append([]string{unit}, args...)...)
+	a, err := prepareUnitArgs("is-active", opts, []string{unit}, nil, args)
+	if err != nil {
+		return false, err
+	}
 	stdout, _, _, err := execute(ctx, a)
 	stdout = strings.TrimSuffix(stdout, "\n")
 	switch stdout {
@@ -52,7 +174,10 @@ func isActive(ctx context.Context, unit string, opts Options, args ...string) (b
 }
 
 func isEnabled(ctx context.Context, unit string, opts Options, args ...string) (bool, error) {
-	a := prepareArgs("is-enabled", opts, append([]string{unit}, args...)...)
+	a, err := prepareUnitArgs("is-enabled", opts, []string{unit}, nil, args)
+	if err != nil {
+		return false, err
+	}
 	stdout, _, _, err := execute(ctx, a)
 	stdout = strings.TrimSuffix(stdout, "\n")
 	switch stdout {
@@ -88,7 +213,10 @@ func isEnabled(ctx context.Context, unit string, opts Options, args ...string) (
 }
 
 func isFailed(ctx context.Context, unit string, opts Options, args ...string) (bool, error) {
-	a := prepareArgs("is-failed", opts, append([]string{unit}, args...)...)
+	a, err := prepareUnitArgs("is-failed", opts, []string{unit}, nil, args)
+	if err != nil {
+		return false, err
+	}
 	stdout, _, _, err := execute(ctx, a)
 	stdout = strings.TrimSuffix(stdout, "\n")
 	switch stdout {
@@ -103,27 +231,131 @@ func isFailed(ctx context.Context, unit string, opts Options, args ...string) (b
 	}
 }
 
-func mask(ctx context.Context, unit string, opts Options, args ...string) error {
-	a := prepareArgs("mask", opts, append([]string{unit}, args...)...)
-	_, _, _, err := execute(ctx, a)
+func isSystemRunning(ctx context.Context, opts Options, args ...string) (SystemState, error) {
+	a, err := prepareUnitArgs("is-system-running", opts, nil, nil, args)
+	if err != nil {
+		return SystemUnknown, err
+	}
+	stdout, _, _, err := execute(ctx, a)
+	state := SystemState(strings.TrimSuffix(stdout, "\n"))
+	// is-system-running exits non-zero unless the state is running.
+	if slices.Contains(systemStates, state) {
+		return state, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("unexpected system state %q: %w", state, ErrUnspecified)
+	}
+	return SystemUnknown, err
+}
+
+func kill(ctx context.Context, unit string, opts Options, args ...string) error {
+	a, err := prepareUnitArgs("kill", opts, []string{unit}, nil, args)
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(args, func(arg string) bool {
+		return arg == "--kill-value" || strings.HasPrefix(arg, "--kill-value=")
+	}) {
+		if err := requireVersion(ctx, versionKillValue, "--kill-value"); err != nil {
+			return err
+		}
+	}
+	_, _, _, err = execute(ctx, a)
+	return err
+}
+
+func link(ctx context.Context, path string, opts Options, args ...string) (ChangeSet, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+	a, err := prepareUnitArgs("link", opts, []string{abs}, nil, args)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	return parseChangeSet(stdout, stderr), err
+}
+
+func listDependencies(ctx context.Context, unit string, dopts DependencyOptions, opts Options, args ...string) (*Dependency, error) {
+	flags := append([]string{"--no-pager", "--full"}, dopts.args()...)
+	a, err := prepareUnitArgs("list-dependencies", opts, []string{unit}, flags, args)
+	if err != nil {
+		return nil, err
+	}
+	stdout, _, _, err := execute(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	return parseDependencyTree(stdout)
+}
+
+func mask(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error) {
+	a, err := prepareUnitArgs("mask", opts, []string{unit}, nil, args)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	return parseChangeSet(stdout, stderr), err
+}
+
+func resetFailed(ctx context.Context, unit string, opts Options, args ...string) error {
+	var units []string
+	if unit != "" {
+		units = []string{unit}
+	}
+	a, err := prepareUnitArgs("reset-failed", opts, units, nil, args)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = execute(ctx, a)
 	return err
 }
 
 func restart(ctx context.Context, unit string, opts Options, args ...string) error {
-	a := prepareArgs("restart", opts, append([]string{unit}, args...)...)
-	_, _, _, err := execute(ctx, a)
+	a, err := prepareUnitArgs("restart", opts, []string{unit}, nil, args)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = execute(ctx, a)
 	return err
 }
 
 func reload(ctx context.Context, unit string, opts Options, args ...string) error {
-	a := prepareArgs("reload", opts, append([]string{unit}, args...)...)
-	_, _, _, err := execute(ctx, a)
+	a, err := prepareUnitArgs("reload", opts, []string{unit}, nil, args)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = execute(ctx, a)
+	return err
+}
+
+func revert(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error) {
+	a, err := prepareUnitArgs("revert", opts, []string{unit}, nil, args)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	return parseChangeSet(stdout, stderr), err
+}
+
+func setDefault(ctx context.Context, target string, opts Options, args ...string) error {
+	a, err := prepareUnitArgs("set-default", opts, []string{targetUnitName(target)}, nil, args)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = execute(ctx, a)
 	return err
 }
 
 func show(ctx context.Context, unit string, property properties.Property, opts Options, args ...string) (string, error) {
-	extra := append([]string{unit, "--property", string(property)}, args...)
-	a := prepareArgs("show", opts, extra...)
+	a, err := prepareUnitArgs("show", opts, []string{unit}, []string{"--property", string(property)}, args)
+	if err != nil {
+		return "", err
+	}
+	if err := requirePropertyVersions(ctx, property); err != nil {
+		return "", err
+	}
 	stdout, _, _, err := execute(ctx, a)
 	stdout = strings.TrimPrefix(stdout, string(property)+"=")
 	stdout = strings.TrimSuffix(stdout, "\n")
@@ -131,25 +363,37 @@ func show(ctx context.Context, unit string, property properties.Property, opts O
 }
 
 func start(ctx context.Context, unit string, opts Options, args ...string) error {
-	a := prepareArgs("start", opts, append([]string{unit}, args...)...)
-	_, _, _, err := execute(ctx, a)
+	a, err := prepareUnitArgs("start", opts, []string{unit}, nil, args)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = execute(ctx, a)
 	return err
 }
 
 func status(ctx context.Context, unit string, opts Options, args ...string) (string, error) {
-	a := prepareArgs("status", opts, append([]string{unit}, args...)...)
+	a, err := prepareUnitArgs("status", opts, []string{unit}, nil, args)
+	if err != nil {
+		return "", err
+	}
 	stdout, _, _, err := execute(ctx, a)
 	return stdout, err
 }
 
 func stop(ctx context.Context, unit string, opts Options, args ...string) error {
-	a := prepareArgs("stop", opts, append([]string{unit}, args...)...)
-	_, _, _, err := execute(ctx, a)
+	a, err := prepareUnitArgs("stop", opts, []string{unit}, nil, args)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = execute(ctx, a)
 	return err
 }
 
-func unmask(ctx context.Context, unit string, opts Options, args ...string) error {
-	a := prepareArgs("unmask", opts, append([]string{unit}, args...)...)
-	_, _, _, err := execute(ctx, a)
-	return err
+func unmask(ctx context.Context, unit string, opts Options, args ...string) (ChangeSet, error) {
+	a, err := prepareUnitArgs("unmask", opts, []string{unit}, nil, args)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+	stdout, stderr, _, err := execute(ctx, a)
+	return parseChangeSet(stdout, stderr), err
 }
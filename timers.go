@@ -0,0 +1,217 @@
+package systemctl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taigrr/systemctl/properties"
+)
+
+// Timer is a timer unit as listed by `systemctl list-timers`.
+type Timer struct {
+	// Unit is the name of the timer unit, e.g. "logrotate.timer".
+	Unit string
+	// Activates is the unit the timer starts when it elapses.
+	Activates string
+	// Next is when the timer elapses next, or the zero Time if it won't.
+	Next time.Time
+	// Left is the time until Next, as of the time of listing.
+	Left time.Duration
+	// Last is when the timer last elapsed, or the zero Time if it never did.
+	Last time.Time
+	// Passed is the time since Last, as of the time of listing.
+	Passed time.Duration
+}
+
+// ListTimers returns all loaded timer units, including inactive ones
+// (`systemctl list-timers --all`).
+//
+// JSON output is requested from systemctl, with a fallback to parsing the
+// text table on systemd versions which don't support it.
+//
+// Any additional arguments are passed directly to the systemctl command.
+func ListTimers(ctx context.Context, opts Options, args ...string) ([]Timer, error) {
+	flags := []string{"--all", "--no-legend", "--full", "--no-pager"}
+	a, err := prepareUnitArgs("list-timers", opts, nil, flags, args)
+	if err != nil {
+		return []Timer{}, err
+	}
+	now := time.Now()
+	if requireVersion(ctx, versionJSONOutput, "--output=json") == nil {
+		stdout, _, _, err := execute(ctx, append(a, "--output=json"))
+		if err == nil && strings.HasPrefix(strings.TrimSpace(stdout), "[") {
+			return parseTimersJSON(stdout, now)
+		}
+	}
+
+	stdout, stderr, _, err := execute(ctx, a)
+	if err != nil {
+		return []Timer{}, errors.Join(err, filterErr(stderr))
+	}
+	return parseTimers(stdout, time.Local, now), nil
+}
+
+func parseTimersJSON(stdout string, now time.Time) ([]Timer, error) {
+	var entries []struct {
+		Next      *int64 `json:"next"`
+		Last      *int64 `json:"last"`
+		Unit      string `json:"unit"`
+		Activates string `json:"activates"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+		return []Timer{}, err
+	}
+	timers := make([]Timer, 0, len(entries))
+	for _, e := range entries {
+		timer := Timer{
+			Unit:      e.Unit,
+			Activates: e.Activates,
+			Next:      usecTime(e.Next),
+			Last:      usecTime(e.Last),
+		}
+		timer.setRelative(now)
+		timers = append(timers, timer)
+	}
+	return timers, nil
+}
+
+// usecTime converts a timestamp in microseconds since the epoch, as found
+// in systemd's JSON output, to a Time. Missing and zero timestamps map to
+// the zero Time.
+func usecTime(usec *int64) time.Time {
+	if usec == nil || *usec <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(*usec)
+}
+
+// parseTimers parses the text table of list-timers. Each row has the
+// columns NEXT LEFT LAST PASSED UNIT ACTIVATES, where NEXT and LAST are
+// timestamps such as "Mon 2024-01-15 00:00:00 UTC" and LEFT and PASSED are
+// time spans ending in "left" and "ago". Any of these four may be "-" or
+// "n/a" instead.
+func parseTimers(stdout string, loc *time.Location, now time.Time) []Timer {
+	timers := []Timer{}
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+		timer := Timer{
+			Unit:      fields[len(fields)-2],
+			Activates: fields[len(fields)-1],
+		}
+		if !strings.HasSuffix(timer.Unit, ".timer") {
+			continue
+		}
+		rest := fields[:len(fields)-2]
+		var ok bool
+		if timer.Next, rest, ok = takeTimestamp(rest, loc); !ok {
+			continue
+		}
+		if rest, ok = takeSpan(rest, "left"); !ok {
+			continue
+		}
+		if timer.Last, rest, ok = takeTimestamp(rest, loc); !ok {
+			continue
+		}
+		if _, ok = takeSpan(rest, "ago"); !ok {
+			continue
+		}
+		timer.setRelative(now)
+		timers = append(timers, timer)
+	}
+	return timers
+}
+
+func takeTimestamp(fields []string, loc *time.Location) (time.Time, []string, bool) {
+	if len(fields) == 0 {
+		return time.Time{}, fields, false
+	}
+	if fields[0] == "-" || fields[0] == "n/a" {
+		return time.Time{}, fields[1:], true
+	}
+	if len(fields) < 4 {
+		return time.Time{}, fields, false
+	}
+	t, err := time.ParseInLocation(dateFormat, strings.Join(fields[:4], " "), loc)
+	if err != nil {
+		return time.Time{}, fields, false
+	}
+	return t, fields[4:], true
+}
+
+func takeSpan(fields []string, suffix string) ([]string, bool) {
+	if len(fields) == 0 {
+		return fields, false
+	}
+	if fields[0] == "-" || fields[0] == "n/a" {
+		return fields[1:], true
+	}
+	for i, field := range fields {
+		if field == suffix {
+			return fields[i+1:], true
+		}
+	}
+	return fields, false
+}
+
+func (t *Timer) setRelative(now time.Time) {
+	if !t.Next.IsZero() {
+		t.Left = t.Next.Sub(now)
+	}
+	if !t.Last.IsZero() {
+		t.Passed = now.Sub(t.Last)
+	}
+}
+
+// GetNextElapse returns when a timer elapses next
+// (`systemctl show [timer] --property NextElapseUSecRealtime`), or the zero
+// Time if it is not scheduled to elapse.
+func GetNextElapse(ctx context.Context, timer string, opts Options) (time.Time, error) {
+	value, err := Show(ctx, timerUnitName(timer), properties.NextElapseUSecRealtime, opts)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseTimestamp(value)
+}
+
+// GetLastTrigger returns when a timer last elapsed
+// (`systemctl show [timer] --property LastTriggerUSec`), or the zero Time if
+// it never did.
+func GetLastTrigger(ctx context.Context, timer string, opts Options) (time.Time, error) {
+	value, err := Show(ctx, timerUnitName(timer), properties.LastTriggerUSec, opts)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseTimestamp(value)
+}
+
+// TriggerTimer starts the unit activated by a timer right away, without
+// waiting for the timer to elapse. The timer's own schedule is unaffected.
+func TriggerTimer(ctx context.Context, timer string, opts Options) error {
+	timer = timerUnitName(timer)
+	value, err := Show(ctx, timer, properties.Triggers, opts.withoutFlags())
+	if err != nil {
+		return err
+	}
+	units := strings.Fields(value)
+	if len(units) == 0 {
+		return fmt.Errorf("%s activates no unit: %w", timer, ErrDoesNotExist)
+	}
+	return Start(ctx, units[0], opts)
+}
+
+// parseTimestamp parses a timestamp as formatted by `systemctl show`.
+// Empty and "n/a" values, used for timestamps which are not set, map to the
+// zero Time.
+func parseTimestamp(value string) (time.Time, error) {
+	if value == "" || value == "n/a" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(dateFormat, value, time.Local)
+}
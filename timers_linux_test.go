@@ -0,0 +1,47 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListTimersFallsBackToText(t *testing.T) {
+	logFile := fakeSystemctl(t, `case "$*" in
+*--output=json*) echo "Unknown output 'json'." >&2; exit 1 ;;
+*) echo "Mon 2024-01-15 00:00:00 UTC 5h left Sun 2024-01-14 00:00:00 UTC 18h ago logrotate.timer logrotate.service" ;;
+esac`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	timers, err := ListTimers(ctx, Options{})
+	if err != nil {
+		t.Fatalf("ListTimers returned error: %v", err)
+	}
+	if len(timers) != 1 || timers[0].Unit != "logrotate.timer" || timers[0].Next.IsZero() {
+		t.Errorf("ListTimers = %+v", timers)
+	}
+	if calls := fakeInvocations(t, logFile); len(calls) != 2 {
+		t.Errorf("expected a JSON attempt and a text fallback, got %v", calls)
+	}
+}
+
+func TestTriggerTimer(t *testing.T) {
+	logFile := fakeSystemctl(t, `if [ "$1" = show ]; then echo "Triggers=backup.service"; fi`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := TriggerTimer(ctx, "backup", Options{}); err != nil {
+		t.Fatalf("TriggerTimer returned error: %v", err)
+	}
+	want := [][]string{
+		{"show", "--system", "backup.timer", "--property", "Triggers"},
+		{"start", "--system", "backup.service"},
+	}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, want) {
+		t.Errorf("invocations = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,88 @@
+package systemctl
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTimers(t *testing.T) {
+	stdout := `Mon 2024-01-15 00:00:00 UTC 5h 2min left    Sun 2024-01-14 00:00:00 UTC 18h ago      logrotate.timer              logrotate.service
+Mon 2024-01-15 06:39:12 UTC 11h left       -                           -            apt-daily-upgrade.timer      apt-daily-upgrade.service
+-                           -              Sun 2024-01-14 12:00:00 UTC 6h ago       oneshot.timer                oneshot.service
+n/a                         n/a            n/a                         n/a          broken.timer                 broken.service
+garbage line`
+
+	now := time.Date(2024, 1, 14, 18, 58, 0, 0, time.UTC)
+	got := parseTimers(stdout, time.UTC, now)
+	want := []Timer{
+		{
+			Unit:      "logrotate.timer",
+			Activates: "logrotate.service",
+			Next:      time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+			Left:      5*time.Hour + 2*time.Minute,
+			Last:      time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC),
+			Passed:    18*time.Hour + 58*time.Minute,
+		},
+		{
+			Unit:      "apt-daily-upgrade.timer",
+			Activates: "apt-daily-upgrade.service",
+			Next:      time.Date(2024, 1, 15, 6, 39, 12, 0, time.UTC),
+			Left:      11*time.Hour + 41*time.Minute + 12*time.Second,
+		},
+		{
+			Unit:      "oneshot.timer",
+			Activates: "oneshot.service",
+			Last:      time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC),
+			Passed:    6*time.Hour + 58*time.Minute,
+		},
+		{Unit: "broken.timer", Activates: "broken.service"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseTimers() returned %d timers, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !got[i].Next.Equal(want[i].Next) || !got[i].Last.Equal(want[i].Last) {
+			t.Errorf("timer %d times = %v / %v, want %v / %v", i, got[i].Next, got[i].Last, want[i].Next, want[i].Last)
+		}
+		got[i].Next, got[i].Last = want[i].Next, want[i].Last
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("timer %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseTimersJSON(t *testing.T) {
+	stdout := `[{"next":1705276800000000,"left":1705276800000000,"last":null,"passed":null,"unit":"logrotate.timer","activates":"logrotate.service"},` +
+		`{"next":0,"left":0,"last":1705190400000000,"passed":1705190400000000,"unit":"oneshot.timer","activates":"oneshot.service"}]`
+	now := time.Date(2024, 1, 14, 18, 0, 0, 0, time.UTC)
+	got, err := parseTimersJSON(stdout, now)
+	if err != nil {
+		t.Fatalf("parseTimersJSON returned error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("parseTimersJSON returned %d timers, want 2", len(got))
+	}
+	if got[0].Unit != "logrotate.timer" || got[0].Activates != "logrotate.service" {
+		t.Errorf("timer 0 = %+v", got[0])
+	}
+	if !got[0].Next.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)) || got[0].Left != 6*time.Hour || !got[0].Last.IsZero() {
+		t.Errorf("timer 0 times = %+v", got[0])
+	}
+	if !got[1].Next.IsZero() || got[1].Passed != 18*time.Hour {
+		t.Errorf("timer 1 times = %+v", got[1])
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	for _, value := range []string{"", "n/a"} {
+		ts, err := parseTimestamp(value)
+		if err != nil || !ts.IsZero() {
+			t.Errorf("parseTimestamp(%q) = %v, %v, want zero time", value, ts, err)
+		}
+	}
+	ts, err := parseTimestamp("Mon 2024-01-15 00:00:00 UTC")
+	if err != nil || !ts.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("parseTimestamp = %v, %v", ts, err)
+	}
+}
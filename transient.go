@@ -0,0 +1,177 @@
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/taigrr/systemctl/properties"
+	"github.com/taigrr/systemctl/unitfile"
+)
+
+// TransientOptions configures a transient unit started by RunTransient.
+// See systemd-run(1) for details on each setting.
+type TransientOptions struct {
+	// Unit is the name of the transient unit. If empty, systemd generates
+	// one, e.g. "run-u42.service".
+	Unit string
+	// Description of the unit, as shown by Status.
+	Description string
+	// Scope runs the command in a transient .scope unit instead of a
+	// .service unit. The command then runs as a child of the calling
+	// process, and RunTransient waits for it to exit. Scope can't be
+	// combined with Wait.
+	Scope bool
+	// Properties sets unit properties, e.g. properties.MemoryMax to "512M".
+	Properties map[properties.Property]string
+	// OnCalendar creates a transient timer which starts the service at the
+	// given calendar event, see systemd.time(7).
+	OnCalendar string
+	// OnActive creates a transient timer which starts the service after
+	// the given time has passed.
+	OnActive time.Duration
+	// Wait waits until the service has finished, so that the Result and
+	// ExitStatus of the returned TransientResult are set.
+	Wait bool
+	// Collect unloads the unit after it finished, even if it failed.
+	Collect bool
+	// WorkingDirectory of the command.
+	WorkingDirectory string
+	// Environment variables passed to the command.
+	Environment map[string]string
+	// User and Group the command runs as, by name or numeric ID.
+	User  string
+	Group string
+}
+
+// TransientResult describes a transient unit started by RunTransient.
+type TransientResult struct {
+	// Unit is the name of the unit running the command. If a timer was
+	// requested, it is the name of the timer unit.
+	Unit string
+	// Result is the final result of the unit as reported by systemd, e.g.
+	// "success", "exit-code" or "timeout". It is only set if the command
+	// was waited for (TransientOptions.Wait or Scope).
+	Result string
+	// ExitStatus is the exit status of the command. It is only set if the
+	// command was waited for.
+	ExitStatus int
+}
+
+// RunTransient runs a command in a transient unit using systemd-run, in its
+// own cgroup with the given properties applied.
+//
+// If the command was waited for, a non-zero exit status or an unsuccessful
+// result is reported through TransientResult rather than as an error; the
+// returned error only reports failures to create or run the unit, or
+// ErrInvalidName if argv is empty.
+func RunTransient(ctx context.Context, argv []string, topts TransientOptions, opts Options) (TransientResult, error) {
+	if systemdRun == "" {
+		return TransientResult{}, ErrSystemdRunNotInstalled
+	}
+	if len(argv) == 0 {
+		return TransientResult{}, fmt.Errorf("no command given: %w", ErrInvalidName)
+	}
+	if topts.Scope && topts.Wait {
+		// systemd-run refuses --wait for scopes, which are waited for anyway.
+		return TransientResult{}, fmt.Errorf("waiting for a scope: %w", ErrFlagNotAllowed)
+	}
+	args := append(managerArgs(opts), transientArgs(topts)...)
+	args = append(args, "--")
+	args = append(args, argv...)
+	_, stderr, code, err := executeCommand(ctx, systemdRun, args)
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		// Decided by the context rather than the exit status, as the
+		// command may legitimately exit with the status used for timeouts.
+		return parseTransientOutput(stderr), errors.Join(ErrExecTimeout, ctxErr)
+	}
+	result := parseTransientOutput(stderr)
+	if (topts.Wait || topts.Scope) && result.Unit != "" {
+		// The unit was started and waited for, so systemd-run exited with
+		// the status of the command rather than reporting its own failure.
+		result.ExitStatus = code
+		if result.Result == "" {
+			result.Result = "success"
+			if code != 0 {
+				result.Result = "exit-code"
+			}
+		}
+		return result, nil
+	}
+	return result, err
+}
+
+func transientArgs(topts TransientOptions) []string {
+	var args []string
+	if topts.Unit != "" {
+		args = append(args, "--unit="+topts.Unit)
+	}
+	if topts.Description != "" {
+		args = append(args, "--description="+topts.Description)
+	}
+	if topts.Scope {
+		args = append(args, "--scope")
+	}
+	props := make([]string, 0, len(topts.Properties))
+	for prop, value := range topts.Properties {
+		props = append(props, string(prop)+"="+value)
+	}
+	sort.Strings(props)
+	for _, prop := range props {
+		args = append(args, "--property="+prop)
+	}
+	if topts.OnCalendar != "" {
+		args = append(args, "--on-calendar="+topts.OnCalendar)
+	}
+	if topts.OnActive != 0 {
+		args = append(args, "--on-active="+unitfile.FormatTimespan(topts.OnActive))
+	}
+	if topts.Wait {
+		args = append(args, "--wait")
+	}
+	if topts.Collect {
+		args = append(args, "--collect")
+	}
+	if topts.WorkingDirectory != "" {
+		args = append(args, "--working-directory="+topts.WorkingDirectory)
+	}
+	env := make([]string, 0, len(topts.Environment))
+	for name, value := range topts.Environment {
+		env = append(env, name+"="+value)
+	}
+	sort.Strings(env)
+	for _, e := range env {
+		args = append(args, "--setenv="+e)
+	}
+	if topts.User != "" {
+		args = append(args, "--uid="+topts.User)
+	}
+	if topts.Group != "" {
+		args = append(args, "--gid="+topts.Group)
+	}
+	return args
+}
+
+// parseTransientOutput extracts the unit name and result from the status
+// lines systemd-run prints on stderr, such as
+// "Running as unit: run-u42.service; invocation ID: ..." and
+// "Finished with result: exit-code".
+func parseTransientOutput(stderr string) TransientResult {
+	result := TransientResult{}
+	for _, line := range strings.Split(stderr, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "Running ") && strings.Contains(line, " as unit: ") && result.Unit == "":
+			_, unit, _ := strings.Cut(line, " as unit: ")
+			unit, _, _ = strings.Cut(unit, ";")
+			result.Unit = strings.TrimSuffix(strings.TrimSpace(unit), ".")
+		case strings.HasPrefix(line, "Finished with result: "):
+			result.Result = strings.TrimPrefix(line, "Finished with result: ")
+		}
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRunTransientWait(t *testing.T) {
+	logFile := fakeBinary(t, &systemdRun, "systemd-run", `cat >&2 <<'EOF'
+Running as unit: run-u42.service; invocation ID: 0123
+Finished with result: exit-code
+Main processes terminated with: code=exited/status=3
+EOF
+exit 3`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	result, err := RunTransient(ctx, []string{"/bin/false", "--flag"}, TransientOptions{Wait: true}, Options{UserMode: true})
+	if err != nil {
+		t.Fatalf("RunTransient returned error: %v", err)
+	}
+	want := TransientResult{Unit: "run-u42.service", Result: "exit-code", ExitStatus: 3}
+	if result != want {
+		t.Errorf("RunTransient = %+v, want %+v", result, want)
+	}
+	wantArgs := [][]string{{"--user", "--wait", "--", "/bin/false", "--flag"}}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
+
+func TestRunTransientFailure(t *testing.T) {
+	fakeBinary(t, &systemdRun, "systemd-run", `echo "Failed to start transient service unit: Interactive authentication required." >&2
+exit 1`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := RunTransient(ctx, []string{"/bin/true"}, TransientOptions{Wait: true}, Options{})
+	if !errors.Is(err, ErrInsufficientPermissions) {
+		t.Errorf("error is %v, but should have been %v", err, ErrInsufficientPermissions)
+	}
+}
+
+func TestRunTransientNoCommand(t *testing.T) {
+	logFile := fakeBinary(t, &systemdRun, "systemd-run", ``)
+	_, err := RunTransient(t.Context(), nil, TransientOptions{}, Options{})
+	if !errors.Is(err, ErrInvalidName) {
+		t.Errorf("error is %v, but should have been %v", err, ErrInvalidName)
+	}
+	if got := fakeInvocations(t, logFile); len(got) != 0 {
+		t.Errorf("invocations = %v, want none", got)
+	}
+}
+
+func TestRunTransientExitStatus130(t *testing.T) {
+	fakeBinary(t, &systemdRun, "systemd-run", `cat >&2 <<'EOF'
+Running as unit: run-u7.service; invocation ID: 0123
+Finished with result: exit-code
+Main processes terminated with: code=exited/status=130
+EOF
+exit 130`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	result, err := RunTransient(ctx, []string{"/usr/bin/app"}, TransientOptions{Wait: true}, Options{})
+	if err != nil {
+		t.Fatalf("RunTransient returned error: %v", err)
+	}
+	if want := (TransientResult{Unit: "run-u7.service", Result: "exit-code", ExitStatus: 130}); result != want {
+		t.Errorf("RunTransient = %+v, want %+v", result, want)
+	}
+}
+
+func TestRunTransientScopeWait(t *testing.T) {
+	logFile := fakeBinary(t, &systemdRun, "systemd-run", ``)
+	_, err := RunTransient(t.Context(), []string{"/bin/true"}, TransientOptions{Scope: true, Wait: true}, Options{})
+	if !errors.Is(err, ErrFlagNotAllowed) {
+		t.Errorf("error is %v, but should have been %v", err, ErrFlagNotAllowed)
+	}
+	if got := fakeInvocations(t, logFile); len(got) != 0 {
+		t.Errorf("invocations = %v, want none", got)
+	}
+}
@@ -0,0 +1,81 @@
+package systemctl
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/taigrr/systemctl/properties"
+)
+
+func TestTransientArgs(t *testing.T) {
+	topts := TransientOptions{
+		Unit:             "backup",
+		Properties:       map[properties.Property]string{properties.MemoryMax: "512M", properties.CPUWeight: "20"},
+		OnActive:         90 * time.Second,
+		Wait:             true,
+		Collect:          true,
+		WorkingDirectory: "/srv",
+		Environment:      map[string]string{"B": "2", "A": "1 2"},
+		User:             "backup",
+	}
+	want := []string{
+		"--unit=backup",
+		"--property=CPUWeight=20",
+		"--property=MemoryMax=512M",
+		"--on-active=90s",
+		"--wait",
+		"--collect",
+		"--working-directory=/srv",
+		"--setenv=A=1 2",
+		"--setenv=B=2",
+		"--uid=backup",
+	}
+	if got := transientArgs(topts); !reflect.DeepEqual(got, want) {
+		t.Errorf("transientArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestParseTransientOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		want   TransientResult
+	}{
+		{
+			name:   "service",
+			stderr: "Running as unit: run-u42.service; invocation ID: 0123456789abcdef\n",
+			want:   TransientResult{Unit: "run-u42.service"},
+		},
+		{
+			name:   "older service",
+			stderr: "Running as unit: run-u42.service\n",
+			want:   TransientResult{Unit: "run-u42.service"},
+		},
+		{
+			name:   "scope",
+			stderr: "Running scope as unit: run-r7.scope\n",
+			want:   TransientResult{Unit: "run-r7.scope"},
+		},
+		{
+			name:   "timer",
+			stderr: "Running timer as unit: run-u9.timer\nWill run service as unit: run-u9.service\n",
+			want:   TransientResult{Unit: "run-u9.timer"},
+		},
+		{
+			name: "waited",
+			stderr: "Running as unit: run-u42.service; invocation ID: 0123\n" +
+				"Finished with result: exit-code\n" +
+				"Main processes terminated with: code=exited/status=3\n" +
+				"Service runtime: 5ms\n",
+			want: TransientResult{Unit: "run-u42.service", Result: "exit-code"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTransientOutput(tt.stderr); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTransientOutput() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
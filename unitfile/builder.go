@@ -0,0 +1,423 @@
+package unitfile
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrIncomplete is returned by the builders when a unit lacks a setting it
+// cannot work without, such as a Service without ExecStart.
+var ErrIncomplete = errors.New("unit file incomplete")
+
+// Directive is a single raw "Key=Value" assignment, used to set directives
+// the builders don't have a field for. Values are written verbatim.
+type Directive struct {
+	Key   string
+	Value string
+}
+
+// UnitSection holds the generic [Unit] settings shared by all unit types.
+// See systemd.unit(5).
+type UnitSection struct {
+	Description   string
+	Documentation []string
+	Requires      []string
+	Wants         []string
+	BindsTo       []string
+	PartOf        []string
+	Conflicts     []string
+	Before        []string
+	After         []string
+	Extra         []Directive
+}
+
+// InstallSection holds the [Install] settings used by enable and disable.
+// See systemd.unit(5).
+type InstallSection struct {
+	WantedBy   []string
+	RequiredBy []string
+	Alias      []string
+	Also       []string
+	Extra      []Directive
+}
+
+// RestartPolicy configures whether a service is restarted when it exits.
+type RestartPolicy string
+
+const (
+	RestartNo         RestartPolicy = "no"
+	RestartAlways     RestartPolicy = "always"
+	RestartOnSuccess  RestartPolicy = "on-success"
+	RestartOnFailure  RestartPolicy = "on-failure"
+	RestartOnAbnormal RestartPolicy = "on-abnormal"
+	RestartOnAbort    RestartPolicy = "on-abort"
+	RestartOnWatchdog RestartPolicy = "on-watchdog"
+)
+
+// Service describes a .service unit. See systemd.service(5) and
+// systemd.exec(5).
+type Service struct {
+	Unit    UnitSection
+	Install InstallSection
+
+	// Type is the process start-up type, e.g. "simple", "exec", "notify"
+	// or "oneshot".
+	Type string
+	// ExecStart is the command line to run, as an argv. Each argument is
+	// quoted and escaped as needed, so it reaches the process unchanged.
+	ExecStart     []string
+	ExecStartPre  [][]string
+	ExecStartPost [][]string
+	ExecReload    []string
+	ExecStop      []string
+
+	Restart         RestartPolicy
+	RestartSec      time.Duration
+	TimeoutStartSec time.Duration
+	TimeoutStopSec  time.Duration
+	RemainAfterExit bool
+
+	User             string
+	Group            string
+	WorkingDirectory string
+	// Environment is written as one Environment= line per variable,
+	// sorted by name.
+	Environment     map[string]string
+	EnvironmentFile []string
+
+	// Hardening options, see systemd.exec(5).
+	DynamicUser           bool
+	NoNewPrivileges       bool
+	PrivateTmp            bool
+	PrivateDevices        bool
+	ProtectKernelTunables bool
+	ProtectKernelModules  bool
+	ProtectControlGroups  bool
+	// ProtectSystem is one of "true", "full" or "strict".
+	ProtectSystem string
+	// ProtectHome is one of "true", "read-only" or "tmpfs".
+	ProtectHome           string
+	ReadWritePaths        []string
+	CapabilityBoundingSet []string
+	AmbientCapabilities   []string
+
+	// Extra holds additional [Service] directives.
+	Extra []Directive
+}
+
+// File renders the service as a unit file.
+func (s Service) File() (*File, error) {
+	if len(s.ExecStart) == 0 {
+		return nil, fmt.Errorf("service has no ExecStart: %w", ErrIncomplete)
+	}
+	f := &File{}
+	s.Unit.render(f)
+	sec := "Service"
+	addString(f, sec, "Type", s.Type)
+	for _, argv := range s.ExecStartPre {
+		addString(f, sec, "ExecStartPre", ExecCommand(argv))
+	}
+	addString(f, sec, "ExecStart", ExecCommand(s.ExecStart))
+	for _, argv := range s.ExecStartPost {
+		addString(f, sec, "ExecStartPost", ExecCommand(argv))
+	}
+	addString(f, sec, "ExecReload", ExecCommand(s.ExecReload))
+	addString(f, sec, "ExecStop", ExecCommand(s.ExecStop))
+	addString(f, sec, "Restart", string(s.Restart))
+	addDuration(f, sec, "RestartSec", s.RestartSec)
+	addDuration(f, sec, "TimeoutStartSec", s.TimeoutStartSec)
+	addDuration(f, sec, "TimeoutStopSec", s.TimeoutStopSec)
+	addBool(f, sec, "RemainAfterExit", s.RemainAfterExit)
+	addString(f, sec, "User", s.User)
+	addString(f, sec, "Group", s.Group)
+	addString(f, sec, "WorkingDirectory", EscapeSpecifiers(s.WorkingDirectory))
+	names := make([]string, 0, len(s.Environment))
+	for name := range s.Environment {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		addString(f, sec, "Environment", QuoteWord(EscapeSpecifiers(name+"="+s.Environment[name])))
+	}
+	for _, file := range s.EnvironmentFile {
+		addString(f, sec, "EnvironmentFile", EscapeSpecifiers(file))
+	}
+	addBool(f, sec, "DynamicUser", s.DynamicUser)
+	addBool(f, sec, "NoNewPrivileges", s.NoNewPrivileges)
+	addBool(f, sec, "PrivateTmp", s.PrivateTmp)
+	addBool(f, sec, "PrivateDevices", s.PrivateDevices)
+	addBool(f, sec, "ProtectKernelTunables", s.ProtectKernelTunables)
+	addBool(f, sec, "ProtectKernelModules", s.ProtectKernelModules)
+	addBool(f, sec, "ProtectControlGroups", s.ProtectControlGroups)
+	addString(f, sec, "ProtectSystem", s.ProtectSystem)
+	addString(f, sec, "ProtectHome", s.ProtectHome)
+	addList(f, sec, "ReadWritePaths", s.ReadWritePaths)
+	addList(f, sec, "CapabilityBoundingSet", s.CapabilityBoundingSet)
+	addList(f, sec, "AmbientCapabilities", s.AmbientCapabilities)
+	addExtra(f, sec, s.Extra)
+	s.Install.render(f)
+	return f, nil
+}
+
+// Timer describes a .timer unit. See systemd.timer(5).
+type Timer struct {
+	Unit    UnitSection
+	Install InstallSection
+
+	// OnCalendar holds calendar event expressions, see systemd.time(7).
+	OnCalendar         []string
+	OnActiveSec        time.Duration
+	OnBootSec          time.Duration
+	OnStartupSec       time.Duration
+	OnUnitActiveSec    time.Duration
+	OnUnitInactiveSec  time.Duration
+	AccuracySec        time.Duration
+	RandomizedDelaySec time.Duration
+	Persistent         bool
+	// TimerUnit is the unit to activate; it defaults to the service with
+	// the same name as the timer.
+	TimerUnit string
+
+	// Extra holds additional [Timer] directives.
+	Extra []Directive
+}
+
+// File renders the timer as a unit file.
+func (t Timer) File() (*File, error) {
+	if len(t.OnCalendar) == 0 && t.OnActiveSec == 0 && t.OnBootSec == 0 &&
+		t.OnStartupSec == 0 && t.OnUnitActiveSec == 0 && t.OnUnitInactiveSec == 0 {
+		return nil, fmt.Errorf("timer has no trigger: %w", ErrIncomplete)
+	}
+	f := &File{}
+	t.Unit.render(f)
+	sec := "Timer"
+	addEach(f, sec, "OnCalendar", t.OnCalendar)
+	addDuration(f, sec, "OnActiveSec", t.OnActiveSec)
+	addDuration(f, sec, "OnBootSec", t.OnBootSec)
+	addDuration(f, sec, "OnStartupSec", t.OnStartupSec)
+	addDuration(f, sec, "OnUnitActiveSec", t.OnUnitActiveSec)
+	addDuration(f, sec, "OnUnitInactiveSec", t.OnUnitInactiveSec)
+	addDuration(f, sec, "AccuracySec", t.AccuracySec)
+	addDuration(f, sec, "RandomizedDelaySec", t.RandomizedDelaySec)
+	addBool(f, sec, "Persistent", t.Persistent)
+	addString(f, sec, "Unit", t.TimerUnit)
+	addExtra(f, sec, t.Extra)
+	t.Install.render(f)
+	return f, nil
+}
+
+// Socket describes a .socket unit. See systemd.socket(5).
+type Socket struct {
+	Unit    UnitSection
+	Install InstallSection
+
+	ListenStream   []string
+	ListenDatagram []string
+	ListenFIFO     []string
+	Accept         bool
+	SocketUser     string
+	SocketGroup    string
+	// SocketMode is the file mode of AF_UNIX sockets and FIFOs, e.g. 0o660.
+	SocketMode uint32
+	// SocketService is the service to activate; it defaults to the service
+	// with the same name as the socket.
+	SocketService string
+
+	// Extra holds additional [Socket] directives.
+	Extra []Directive
+}
+
+// File renders the socket as a unit file.
+func (s Socket) File() (*File, error) {
+	if len(s.ListenStream) == 0 && len(s.ListenDatagram) == 0 && len(s.ListenFIFO) == 0 {
+		return nil, fmt.Errorf("socket has no listen address: %w", ErrIncomplete)
+	}
+	f := &File{}
+	s.Unit.render(f)
+	sec := "Socket"
+	addEach(f, sec, "ListenStream", s.ListenStream)
+	addEach(f, sec, "ListenDatagram", s.ListenDatagram)
+	addEach(f, sec, "ListenFIFO", s.ListenFIFO)
+	addBool(f, sec, "Accept", s.Accept)
+	addString(f, sec, "SocketUser", s.SocketUser)
+	addString(f, sec, "SocketGroup", s.SocketGroup)
+	if s.SocketMode != 0 {
+		f.Add(sec, "SocketMode", fmt.Sprintf("%04o", s.SocketMode))
+	}
+	addString(f, sec, "Service", s.SocketService)
+	addExtra(f, sec, s.Extra)
+	s.Install.render(f)
+	return f, nil
+}
+
+// Path describes a .path unit. See systemd.path(5).
+type Path struct {
+	Unit    UnitSection
+	Install InstallSection
+
+	PathExists        []string
+	PathExistsGlob    []string
+	PathChanged       []string
+	PathModified      []string
+	DirectoryNotEmpty []string
+	MakeDirectory     bool
+	// PathUnit is the unit to activate; it defaults to the service with the
+	// same name as the path unit.
+	PathUnit string
+
+	// Extra holds additional [Path] directives.
+	Extra []Directive
+}
+
+// File renders the path unit as a unit file.
+func (p Path) File() (*File, error) {
+	if len(p.PathExists) == 0 && len(p.PathExistsGlob) == 0 && len(p.PathChanged) == 0 &&
+		len(p.PathModified) == 0 && len(p.DirectoryNotEmpty) == 0 {
+		return nil, fmt.Errorf("path unit watches nothing: %w", ErrIncomplete)
+	}
+	f := &File{}
+	p.Unit.render(f)
+	sec := "Path"
+	addEach(f, sec, "PathExists", p.PathExists)
+	addEach(f, sec, "PathExistsGlob", p.PathExistsGlob)
+	addEach(f, sec, "PathChanged", p.PathChanged)
+	addEach(f, sec, "PathModified", p.PathModified)
+	addEach(f, sec, "DirectoryNotEmpty", p.DirectoryNotEmpty)
+	addBool(f, sec, "MakeDirectory", p.MakeDirectory)
+	addString(f, sec, "Unit", p.PathUnit)
+	addExtra(f, sec, p.Extra)
+	p.Install.render(f)
+	return f, nil
+}
+
+// Mount describes a .mount unit. See systemd.mount(5).
+//
+// Note that systemd requires a mount unit to be named after the escaped
+// mount point, e.g. "home-data.mount" for Where=/home/data.
+type Mount struct {
+	Unit    UnitSection
+	Install InstallSection
+
+	What       string
+	Where      string
+	Type       string
+	Options    string
+	TimeoutSec time.Duration
+
+	// Extra holds additional [Mount] directives.
+	Extra []Directive
+}
+
+// File renders the mount as a unit file.
+func (m Mount) File() (*File, error) {
+	if m.What == "" || m.Where == "" {
+		return nil, fmt.Errorf("mount needs What and Where: %w", ErrIncomplete)
+	}
+	f := &File{}
+	m.Unit.render(f)
+	sec := "Mount"
+	addString(f, sec, "What", EscapeSpecifiers(m.What))
+	addString(f, sec, "Where", EscapeSpecifiers(m.Where))
+	addString(f, sec, "Type", m.Type)
+	addString(f, sec, "Options", EscapeSpecifiers(m.Options))
+	addDuration(f, sec, "TimeoutSec", m.TimeoutSec)
+	addExtra(f, sec, m.Extra)
+	m.Install.render(f)
+	return f, nil
+}
+
+func (u UnitSection) render(f *File) {
+	sec := "Unit"
+	addString(f, sec, "Description", EscapeSpecifiers(u.Description))
+	addEach(f, sec, "Documentation", u.Documentation)
+	addList(f, sec, "Requires", u.Requires)
+	addList(f, sec, "Wants", u.Wants)
+	addList(f, sec, "BindsTo", u.BindsTo)
+	addList(f, sec, "PartOf", u.PartOf)
+	addList(f, sec, "Conflicts", u.Conflicts)
+	addList(f, sec, "Before", u.Before)
+	addList(f, sec, "After", u.After)
+	addExtra(f, sec, u.Extra)
+}
+
+func (i InstallSection) render(f *File) {
+	sec := "Install"
+	addList(f, sec, "WantedBy", i.WantedBy)
+	addList(f, sec, "RequiredBy", i.RequiredBy)
+	addList(f, sec, "Alias", i.Alias)
+	addList(f, sec, "Also", i.Also)
+	addExtra(f, sec, i.Extra)
+}
+
+// ExecCommand renders an argv as the value of an Exec*= directive. Each
+// argument is quoted with QuoteWord, "%" is escaped as "%%" so it isn't
+// taken for a specifier, and "$" is escaped as "$$" so it isn't taken for
+// an environment variable reference.
+func ExecCommand(argv []string) string {
+	words := make([]string, len(argv))
+	for i, arg := range argv {
+		words[i] = QuoteWord(strings.ReplaceAll(EscapeSpecifiers(arg), "$", "$$"))
+	}
+	return strings.Join(words, " ")
+}
+
+// EscapeSpecifiers escapes "%" as "%%", so systemd does not expand
+// specifiers such as "%i" or "%h" in the value.
+func EscapeSpecifiers(value string) string {
+	return strings.ReplaceAll(value, "%", "%%")
+}
+
+// FormatTimespan renders a duration as a systemd time span, e.g. "90s" or
+// "250ms". See systemd.time(7).
+func FormatTimespan(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "0"
+	case d%time.Second == 0:
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	case d%time.Millisecond == 0:
+		return strconv.FormatInt(int64(d/time.Millisecond), 10) + "ms"
+	default:
+		return strconv.FormatInt(int64(d/time.Microsecond), 10) + "us"
+	}
+}
+
+func addString(f *File, section string, key string, value string) {
+	if value != "" {
+		f.Add(section, key, value)
+	}
+}
+
+func addBool(f *File, section string, key string, value bool) {
+	if value {
+		f.Add(section, key, "yes")
+	}
+}
+
+func addDuration(f *File, section string, key string, value time.Duration) {
+	if value != 0 {
+		f.Add(section, key, FormatTimespan(value))
+	}
+}
+
+func addList(f *File, section string, key string, values []string) {
+	if len(values) > 0 {
+		f.Add(section, key, JoinWords(values))
+	}
+}
+
+func addEach(f *File, section string, key string, values []string) {
+	for _, value := range values {
+		f.Add(section, key, value)
+	}
+}
+
+func addExtra(f *File, section string, extra []Directive) {
+	for _, d := range extra {
+		f.Add(section, d.Key, d.Value)
+	}
+}
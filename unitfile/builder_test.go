@@ -0,0 +1,165 @@
+package unitfile
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestServiceFile(t *testing.T) {
+	s := Service{
+		Unit: UnitSection{
+			Description: "Example daemon at 100% capacity",
+			After:       []string{"network-online.target"},
+			Wants:       []string{"network-online.target"},
+		},
+		Install: InstallSection{WantedBy: []string{"multi-user.target"}},
+		Type:    "exec",
+		ExecStart: []string{
+			"/usr/local/bin/daemon",
+			"--listen=:8080",
+			"--motd=hello world",
+			"--price=$5",
+			";",
+		},
+		Restart:         RestartOnFailure,
+		RestartSec:      5 * time.Second,
+		User:            "daemon",
+		Environment:     map[string]string{"LOG_LEVEL": "debug", "GREETING": "hi there"},
+		NoNewPrivileges: true,
+		ProtectSystem:   "strict",
+		ReadWritePaths:  []string{"/var/lib/daemon", "/srv/my data"},
+		Extra:           []Directive{{Key: "LimitNOFILE", Value: "65536"}},
+	}
+	f, err := s.File()
+	if err != nil {
+		t.Fatalf("File returned error: %v", err)
+	}
+	want := `[Unit]
+Description=Example daemon at 100%% capacity
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=exec
+ExecStart=/usr/local/bin/daemon --listen=:8080 "--motd=hello world" --price=$$5 \;
+Restart=on-failure
+RestartSec=5s
+User=daemon
+Environment="GREETING=hi there"
+Environment=LOG_LEVEL=debug
+NoNewPrivileges=yes
+ProtectSystem=strict
+ReadWritePaths=/var/lib/daemon "/srv/my data"
+LimitNOFILE=65536
+
+[Install]
+WantedBy=multi-user.target
+`
+	if got := f.String(); got != want {
+		t.Errorf("Service.File():\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	parsed, err := ParseString(f.String())
+	if err != nil {
+		t.Fatalf("rendered service does not parse: %v", err)
+	}
+	execStart, _ := parsed.Value("Service", "ExecStart")
+	words, err := SplitWords(execStart)
+	if err != nil {
+		t.Fatalf("SplitWords returned error: %v", err)
+	}
+	if !reflect.DeepEqual(words, []string{"/usr/local/bin/daemon", "--listen=:8080", "--motd=hello world", "--price=$$5", ";"}) {
+		t.Errorf("ExecStart words = %q", words)
+	}
+}
+
+func TestTimerSocketPathMountFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() (*File, error)
+		want string
+	}{
+		{
+			name: "timer",
+			fn: Timer{
+				Unit:        UnitSection{Description: "Nightly backup"},
+				Install:     InstallSection{WantedBy: []string{"timers.target"}},
+				OnCalendar:  []string{"*-*-* 02:00:00"},
+				Persistent:  true,
+				AccuracySec: time.Minute,
+			}.File,
+			want: "[Unit]\nDescription=Nightly backup\n\n[Timer]\nOnCalendar=*-*-* 02:00:00\nAccuracySec=60s\nPersistent=yes\n\n[Install]\nWantedBy=timers.target\n",
+		},
+		{
+			name: "socket",
+			fn: Socket{
+				ListenStream: []string{"/run/app.sock"},
+				SocketMode:   0o660,
+			}.File,
+			want: "[Socket]\nListenStream=/run/app.sock\nSocketMode=0660\n",
+		},
+		{
+			name: "path",
+			fn: Path{
+				PathChanged:   []string{"/etc/app/config.yaml"},
+				MakeDirectory: true,
+				PathUnit:      "app-reload.service",
+			}.File,
+			want: "[Path]\nPathChanged=/etc/app/config.yaml\nMakeDirectory=yes\nUnit=app-reload.service\n",
+		},
+		{
+			name: "mount",
+			fn: Mount{
+				What:    "/dev/disk/by-label/data",
+				Where:   "/srv/data",
+				Type:    "ext4",
+				Options: "noatime",
+				Install: InstallSection{WantedBy: []string{"local-fs.target"}},
+			}.File,
+			want: "[Mount]\nWhat=/dev/disk/by-label/data\nWhere=/srv/data\nType=ext4\nOptions=noatime\n\n[Install]\nWantedBy=local-fs.target\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.fn()
+			if err != nil {
+				t.Fatalf("File returned error: %v", err)
+			}
+			if got := f.String(); got != tt.want {
+				t.Errorf("File():\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildersRejectIncompleteUnits(t *testing.T) {
+	builders := map[string]func() (*File, error){
+		"service": Service{}.File,
+		"timer":   Timer{}.File,
+		"socket":  Socket{}.File,
+		"path":    Path{}.File,
+		"mount":   Mount{Where: "/srv"}.File,
+	}
+	for name, fn := range builders {
+		if _, err := fn(); !errors.Is(err, ErrIncomplete) {
+			t.Errorf("%s: error is %v, but should have been %v", name, err, ErrIncomplete)
+		}
+	}
+}
+
+func TestFormatTimespan(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                       "0",
+		90 * time.Second:        "90s",
+		1500 * time.Millisecond: "1500ms",
+		1500 * time.Microsecond: "1500us",
+	}
+	for d, want := range tests {
+		if got := FormatTimespan(d); got != want {
+			t.Errorf("FormatTimespan(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package unitfile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SplitWords splits a value into words the way systemd splits command lines
+// and other whitespace-separated lists: words are separated by whitespace,
+// single or double quotes group characters into one word, and C-style
+// escapes such as "\n", "\"" or "\x41" are decoded.
+func SplitWords(value string) ([]string, error) {
+	words := []string{}
+	var (
+		word    strings.Builder
+		inWord  bool
+		quote   byte
+		escaped bool
+	)
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case escaped:
+			escaped = false
+			n, err := unescape(value, i, &word)
+			if err != nil {
+				return nil, err
+			}
+			i += n
+		case c == '\\':
+			escaped, inWord = true, true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				word.WriteByte(c)
+			}
+		case c == '"' || c == '\'':
+			quote, inWord = c, true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash in %q: %w", value, ErrSyntax)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q: %w", value, ErrSyntax)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// unescape decodes the escape sequence whose first character (after the
+// backslash) is at value[i], and returns the number of additional bytes
+// consumed.
+func unescape(value string, i int, word *strings.Builder) (int, error) {
+	simple := map[byte]byte{
+		'a': '\a', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t',
+		'v': '\v', 's': ' ', '\\': '\\', '"': '"', '\'': '\'', ';': ';',
+	}
+	c := value[i]
+	if r, ok := simple[c]; ok {
+		word.WriteByte(r)
+		return 0, nil
+	}
+	switch {
+	case c == 'x' && i+2 < len(value):
+		n, err := strconv.ParseUint(value[i+1:i+3], 16, 8)
+		if err == nil {
+			word.WriteByte(byte(n))
+			return 2, nil
+		}
+	case c == 'u' && i+4 < len(value):
+		n, err := strconv.ParseUint(value[i+1:i+5], 16, 32)
+		if err == nil {
+			word.WriteRune(rune(n))
+			return 4, nil
+		}
+	case c == 'U' && i+8 < len(value):
+		n, err := strconv.ParseUint(value[i+1:i+9], 16, 32)
+		if err == nil {
+			word.WriteRune(rune(n))
+			return 8, nil
+		}
+	case c >= '0' && c <= '7' && i+2 < len(value):
+		n, err := strconv.ParseUint(value[i:i+3], 8, 8)
+		if err == nil {
+			word.WriteByte(byte(n))
+			return 2, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid escape sequence \\%c in %q: %w", c, value, ErrSyntax)
+}
+
+// QuoteWord quotes a word so that SplitWords, and systemd, read it back as a
+// single word. Words without whitespace, quotes or backslashes are returned
+// unchanged. A lone ";" is escaped as "\;", as systemd takes it for a
+// command separator in Exec*= directives.
+func QuoteWord(word string) string {
+	if word == ";" {
+		return `\;`
+	}
+	if word != "" && !strings.ContainsAny(word, " \t\n\r\"'\\") {
+		return word
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(word); i++ {
+		switch c := word[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// JoinWords quotes each word with QuoteWord and joins them with spaces.
+func JoinWords(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = QuoteWord(w)
+	}
+	return strings.Join(quoted, " ")
+}
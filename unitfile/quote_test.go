@@ -0,0 +1,52 @@
+package unitfile
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: "", want: []string{}},
+		{value: "/bin/echo hello world", want: []string{"/bin/echo", "hello", "world"}},
+		{value: `/usr/sbin/nginx -g 'daemon on; master_process on;'`, want: []string{"/usr/sbin/nginx", "-g", "daemon on; master_process on;"}},
+		{value: `"a b"c 'd'`, want: []string{"a bc", "d"}},
+		{value: `one\stwo "tab\there" \x41\101`, want: []string{"one two", "tab\there", "AA"}},
+		{value: `"" x`, want: []string{"", "x"}},
+		{value: `"quote \" inside"`, want: []string{`quote " inside`}},
+	}
+	for _, tt := range tests {
+		got, err := SplitWords(tt.value)
+		if err != nil {
+			t.Errorf("SplitWords(%q) returned error: %v", tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitWords(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{`"unterminated`, `trailing\`, `bad\q`} {
+		if _, err := SplitWords(value); !errors.Is(err, ErrSyntax) {
+			t.Errorf("SplitWords(%q) error is %v, but should have been %v", value, err, ErrSyntax)
+		}
+	}
+}
+
+func TestQuoteWordRoundTrip(t *testing.T) {
+	words := []string{"/usr/bin/app", "--name=hello world", "", `back\slash`, `"quoted"`, "it's", "line\nbreak", ";"}
+	got, err := SplitWords(JoinWords(words))
+	if err != nil {
+		t.Fatalf("SplitWords returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, words) {
+		t.Errorf("round trip = %q, want %q", got, words)
+	}
+	if QuoteWord("plain") != "plain" {
+		t.Errorf("QuoteWord quoted a plain word")
+	}
+}
@@ -0,0 +1,379 @@
+// Package unitfile parses and serializes systemd unit files.
+//
+// The parser understands the INI dialect described in systemd.syntax(7):
+// sections, repeated keys, empty assignments which reset list settings,
+// line continuations and comments. Parsed files keep their comments and the
+// order of sections and entries, so a file which is parsed and serialized
+// without modification is reproduced byte for byte.
+package unitfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ErrSyntax is returned when a unit file cannot be parsed.
+var ErrSyntax = errors.New("unit file syntax error")
+
+// File is a parsed unit file or drop-in.
+type File struct {
+	// Path is the location the file was read from, if known. It is set by
+	// ParseCat from the "# /path" separators systemctl cat prints.
+	Path     string
+	Sections []*Section
+	// Trailer holds the comment and blank lines after the last entry.
+	Trailer []string
+
+	missingNewline bool
+}
+
+// Section is a "[Name]" section of a unit file. A name may appear more than
+// once in a file, in which case systemd merges the sections in order.
+type Section struct {
+	Name string
+	// Comments holds the comment and blank lines preceding the section header.
+	Comments []string
+	Entries  []*Entry
+
+	raw string
+}
+
+// Entry is a single "Key=Value" assignment.
+type Entry struct {
+	Key string
+	// Value is the assigned value with surrounding whitespace removed and
+	// line continuations joined. An empty Value resets list settings.
+	Value string
+	// Comments holds the comment and blank lines preceding the entry.
+	Comments []string
+
+	raw      string
+	rawKey   string
+	rawValue string
+}
+
+// Parse reads a unit file.
+func Parse(r io.Reader) (*File, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseString(string(b))
+}
+
+// ParseString parses the content of a unit file.
+func ParseString(content string) (*File, error) {
+	f := &File{}
+	if content == "" {
+		return f, nil
+	}
+	f.missingNewline = !strings.HasSuffix(content, "\n")
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+
+	var (
+		section  *Section
+		comments []string
+	)
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || isComment(trimmed):
+			comments = append(comments, line)
+		case strings.HasPrefix(trimmed, "["):
+			if !strings.HasSuffix(trimmed, "]") || len(trimmed) < 3 {
+				return nil, fmt.Errorf("line %d: invalid section header %q: %w", i+1, trimmed, ErrSyntax)
+			}
+			section = &Section{
+				Name:     trimmed[1 : len(trimmed)-1],
+				Comments: comments,
+				raw:      line,
+			}
+			comments = nil
+			f.Sections = append(f.Sections, section)
+		default:
+			if section == nil {
+				return nil, fmt.Errorf("line %d: assignment outside of section: %w", i+1, ErrSyntax)
+			}
+			start := i
+			raw := []string{line}
+			continued := strings.HasSuffix(line, "\\")
+			logical := strings.TrimSuffix(line, "\\")
+			for continued && i+1 < len(lines) {
+				i++
+				raw = append(raw, lines[i])
+				// Comment lines inside a continuation are ignored.
+				if isComment(strings.TrimSpace(lines[i])) {
+					continue
+				}
+				// The backslash is replaced by a space.
+				logical += " " + strings.TrimSuffix(lines[i], "\\")
+				continued = strings.HasSuffix(lines[i], "\\")
+			}
+			key, value, ok := strings.Cut(logical, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: missing '=' in %q: %w", start+1, trimmed, ErrSyntax)
+			}
+			key = strings.TrimSpace(key)
+			if key == "" {
+				return nil, fmt.Errorf("line %d: empty key: %w", start+1, ErrSyntax)
+			}
+			value = strings.TrimSpace(value)
+			section.Entries = append(section.Entries, &Entry{
+				Key:      key,
+				Value:    value,
+				Comments: comments,
+				raw:      strings.Join(raw, "\n"),
+				rawKey:   key,
+				rawValue: value,
+			})
+			comments = nil
+		}
+	}
+	f.Trailer = comments
+	return f, nil
+}
+
+func isComment(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";")
+}
+
+// String serializes the file. Sections and entries which were parsed and
+// not modified are written exactly as they were read.
+func (f *File) String() string {
+	var b strings.Builder
+	writeLines := func(lines []string) {
+		for _, line := range lines {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+	for _, s := range f.Sections {
+		writeLines(s.Comments)
+		if s.raw != "" && strings.TrimSpace(s.raw) == "["+s.Name+"]" {
+			b.WriteString(s.raw)
+		} else {
+			b.WriteString("[" + s.Name + "]")
+		}
+		b.WriteByte('\n')
+		for _, e := range s.Entries {
+			writeLines(e.Comments)
+			if e.raw != "" && e.Key == e.rawKey && e.Value == e.rawValue {
+				b.WriteString(e.raw)
+			} else {
+				b.WriteString(e.Key + "=" + e.Value)
+			}
+			b.WriteByte('\n')
+		}
+	}
+	writeLines(f.Trailer)
+	out := b.String()
+	if f.missingNewline {
+		out = strings.TrimSuffix(out, "\n")
+	}
+	return out
+}
+
+// WriteTo writes the serialized file to w.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, f.String())
+	return int64(n), err
+}
+
+// Section returns the first section with the given name, or nil.
+func (f *File) Section(name string) *Section {
+	for _, s := range f.Sections {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// AddSection returns the first section with the given name, appending a new
+// empty section to the file if there is none.
+func (f *File) AddSection(name string) *Section {
+	if s := f.Section(name); s != nil {
+		return s
+	}
+	s := &Section{Name: name}
+	if len(f.Sections) > 0 {
+		// Separate the new section from the previous one.
+		s.Comments = []string{""}
+	}
+	f.Sections = append(f.Sections, s)
+	return s
+}
+
+// Value returns the last value assigned to key in all sections with the
+// given name, and whether the key was assigned at all.
+func (f *File) Value(section string, key string) (string, bool) {
+	value, found := "", false
+	for _, s := range f.Sections {
+		if s.Name != section {
+			continue
+		}
+		for _, e := range s.Entries {
+			if e.Key == key {
+				value, found = e.Value, true
+			}
+		}
+	}
+	return value, found
+}
+
+// Values returns the effective list of values assigned to key in all
+// sections with the given name. As in systemd, an empty assignment resets
+// the list, discarding all values assigned before it.
+func (f *File) Values(section string, key string) []string {
+	values := []string{}
+	for _, s := range f.Sections {
+		if s.Name != section {
+			continue
+		}
+		for _, e := range s.Entries {
+			if e.Key != key {
+				continue
+			}
+			if e.Value == "" {
+				values = []string{}
+				continue
+			}
+			values = append(values, e.Value)
+		}
+	}
+	return values
+}
+
+// Set assigns value to key, replacing all existing assignments of key in
+// sections with the given name. The first existing assignment is updated in
+// place so its comments are kept; if there is none, the entry is appended
+// to the first section with that name, which is created if needed.
+func (f *File) Set(section string, key string, value string) {
+	var kept *Entry
+	for _, s := range f.Sections {
+		if s.Name != section {
+			continue
+		}
+		entries := s.Entries[:0]
+		for _, e := range s.Entries {
+			if e.Key == key {
+				if kept != nil {
+					continue
+				}
+				kept = e
+				e.Value = value
+			}
+			entries = append(entries, e)
+		}
+		s.Entries = entries
+	}
+	if kept == nil {
+		f.Add(section, key, value)
+	}
+}
+
+// Add appends an assignment of value to key to the last section with the
+// given name, creating the section if needed. For list settings this adds
+// to the list instead of replacing it.
+func (f *File) Add(section string, key string, value string) {
+	var target *Section
+	for _, s := range f.Sections {
+		if s.Name == section {
+			target = s
+		}
+	}
+	if target == nil {
+		target = f.AddSection(section)
+	}
+	target.Entries = append(target.Entries, &Entry{Key: key, Value: value})
+}
+
+// Delete removes all assignments of key from sections with the given name.
+// Comments preceding a removed entry are removed as well.
+func (f *File) Delete(section string, key string) {
+	for _, s := range f.Sections {
+		if s.Name != section {
+			continue
+		}
+		entries := s.Entries[:0]
+		for _, e := range s.Entries {
+			if e.Key != key {
+				entries = append(entries, e)
+			}
+		}
+		s.Entries = entries
+	}
+}
+
+var unitTypes = []string{
+	"service", "socket", "device", "mount", "automount", "swap",
+	"target", "path", "timer", "slice", "scope",
+}
+
+// isCatSeparator reports whether line is a "# /path" separator printed by
+// `systemctl cat`, naming either a unit file or a drop-in. Other comments
+// starting with a path, such as "# /usr/bin/app --flag", are not.
+func isCatSeparator(line string) bool {
+	p, ok := strings.CutPrefix(line, "# /")
+	if !ok || strings.ContainsAny(p, " \t") {
+		return false
+	}
+	name := path.Base(p)
+	if dir := path.Dir(p); strings.HasSuffix(dir, ".d") && strings.HasSuffix(name, ".conf") {
+		name = strings.TrimSuffix(path.Base(dir), ".d")
+	}
+	i := strings.LastIndex(name, ".")
+	return i > 0 && slices.Contains(unitTypes, name[i+1:])
+}
+
+// ParseCat parses the output of `systemctl cat`, which concatenates a unit's
+// fragment and drop-ins, each preceded by a "# /path/to/file" line. The
+// returned files are in the order printed, with Path set from the
+// separators.
+func ParseCat(output string) ([]*File, error) {
+	type chunk struct {
+		path  string
+		lines []string
+	}
+	var chunks []*chunk
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	for i, line := range lines {
+		if isCatSeparator(line) && (i == 0 || lines[i-1] == "") {
+			if n := len(chunks); n > 0 {
+				prev := chunks[n-1]
+				// systemctl separates files with a blank line.
+				prev.lines = prev.lines[:len(prev.lines)-1]
+			}
+			chunks = append(chunks, &chunk{path: strings.TrimPrefix(line, "# ")})
+			continue
+		}
+		if len(chunks) == 0 {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: expected \"# /path\" separator: %w", i+1, ErrSyntax)
+		}
+		c := chunks[len(chunks)-1]
+		c.lines = append(c.lines, line)
+	}
+
+	files := make([]*File, 0, len(chunks))
+	for _, c := range chunks {
+		content := ""
+		if len(c.lines) > 0 {
+			content = strings.Join(c.lines, "\n") + "\n"
+		}
+		f, err := ParseString(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.path, err)
+		}
+		f.Path = c.path
+		files = append(files, f)
+	}
+	return files, nil
+}
@@ -0,0 +1,196 @@
+package unitfile
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const nginxUnit = `# Vendor unit for nginx
+[Unit]
+Description=A high performance web server
+After=network-online.target remote-fs.target nss-lookup.target
+Wants=network-online.target
+
+[Service]
+Type=forking
+; pre-flight check
+ExecStartPre=/usr/sbin/nginx -t -q -g 'daemon on; master_process on;'
+ExecStart=/usr/sbin/nginx \
+	-g 'daemon on; master_process on;'
+ExecReload=/usr/sbin/nginx -g 'daemon on; master_process on;' -s reload
+Environment=A=1
+Environment=
+Environment=B=2 "C=3 4"
+  Nice = 5  
+
+[Install]
+WantedBy=multi-user.target
+`
+
+func TestParseRoundTrip(t *testing.T) {
+	inputs := []string{
+		nginxUnit,
+		"",
+		"# only a comment\n",
+		"[Unit]\nDescription=no trailing newline",
+		"[Service]\nExecStart=/bin/true \\\n# ignored comment\n  --flag\n\n# trailing comment\n",
+	}
+	for _, input := range inputs {
+		f, err := ParseString(input)
+		if err != nil {
+			t.Fatalf("ParseString(%q) returned error: %v", input, err)
+		}
+		if got := f.String(); got != input {
+			t.Errorf("round trip changed file:\ngot:\n%s\nwant:\n%s", got, input)
+		}
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	f, err := ParseString(nginxUnit)
+	if err != nil {
+		t.Fatalf("ParseString returned error: %v", err)
+	}
+	if len(f.Sections) != 3 {
+		t.Fatalf("got %d sections, want 3", len(f.Sections))
+	}
+	if got := f.Sections[0].Comments; !reflect.DeepEqual(got, []string{"# Vendor unit for nginx"}) {
+		t.Errorf("section comments = %q", got)
+	}
+	if v, ok := f.Value("Service", "ExecStart"); !ok || v != "/usr/sbin/nginx  \t-g 'daemon on; master_process on;'" {
+		t.Errorf("ExecStart = %q, %v", v, ok)
+	}
+	if v, _ := f.Value("Service", "Nice"); v != "5" {
+		t.Errorf("Nice = %q, want %q", v, "5")
+	}
+	if _, ok := f.Value("Service", "User"); ok {
+		t.Errorf("User reported as set")
+	}
+	if got := f.Values("Service", "Environment"); !reflect.DeepEqual(got, []string{`B=2 "C=3 4"`}) {
+		t.Errorf("Environment = %q after reset", got)
+	}
+	if got := f.Values("Unit", "Wants"); !reflect.DeepEqual(got, []string{"network-online.target"}) {
+		t.Errorf("Wants = %q", got)
+	}
+}
+
+func TestParseContinuationSkipsComments(t *testing.T) {
+	f, err := ParseString("[Service]\nExecStart=/bin/echo \\\n# comment\n; other\n  hello\n")
+	if err != nil {
+		t.Fatalf("ParseString returned error: %v", err)
+	}
+	if v, _ := f.Value("Service", "ExecStart"); v != "/bin/echo    hello" {
+		t.Errorf("ExecStart = %q", v)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	inputs := []string{
+		"Description=outside\n",
+		"[Unit\n",
+		"[Unit]\nno equals sign\n",
+		"[Unit]\n=value\n",
+	}
+	for _, input := range inputs {
+		if _, err := ParseString(input); !errors.Is(err, ErrSyntax) {
+			t.Errorf("ParseString(%q) error is %v, but should have been %v", input, err, ErrSyntax)
+		}
+	}
+}
+
+func TestModify(t *testing.T) {
+	f, err := ParseString(nginxUnit)
+	if err != nil {
+		t.Fatalf("ParseString returned error: %v", err)
+	}
+	f.Set("Service", "Type", "notify")
+	f.Set("Service", "Environment", "D=4")
+	f.Add("Install", "WantedBy", "graphical.target")
+	f.Delete("Service", "ExecReload")
+	f.Set("Service", "User", "www-data")
+	f.Set("X-Custom", "Key", "value")
+
+	want := `# Vendor unit for nginx
+[Unit]
+Description=A high performance web server
+After=network-online.target remote-fs.target nss-lookup.target
+Wants=network-online.target
+
+[Service]
+Type=notify
+; pre-flight check
+ExecStartPre=/usr/sbin/nginx -t -q -g 'daemon on; master_process on;'
+ExecStart=/usr/sbin/nginx \
+	-g 'daemon on; master_process on;'
+Environment=D=4
+  Nice = 5  
+User=www-data
+
+[Install]
+WantedBy=multi-user.target
+WantedBy=graphical.target
+
+[X-Custom]
+Key=value
+`
+	if got := f.String(); got != want {
+		t.Errorf("modified file:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseCat(t *testing.T) {
+	output := `# /lib/systemd/system/nginx.service
+[Unit]
+Description=nginx
+
+[Service]
+ExecStart=/usr/sbin/nginx
+
+# /etc/systemd/system/nginx.service.d/override.conf
+# /not/a/separator
+[Service]
+LimitNOFILE=65536
+
+# /etc/systemd/system/nginx.service.d/restart.conf
+[Service]
+Restart=always
+
+# /usr/bin/app --config /etc/app.service
+ExecStartPre=/bin/true
+`
+	files, err := ParseCat(output)
+	if err != nil {
+		t.Fatalf("ParseCat returned error: %v", err)
+	}
+	paths := []string{}
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	wantPaths := []string{
+		"/lib/systemd/system/nginx.service",
+		"/etc/systemd/system/nginx.service.d/override.conf",
+		"/etc/systemd/system/nginx.service.d/restart.conf",
+	}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Fatalf("paths = %q, want %q", paths, wantPaths)
+	}
+	if got := files[0].String(); got != "[Unit]\nDescription=nginx\n\n[Service]\nExecStart=/usr/sbin/nginx\n" {
+		t.Errorf("fragment = %q", got)
+	}
+	if got := files[1].Sections[0].Comments; !reflect.DeepEqual(got, []string{"# /not/a/separator"}) {
+		t.Errorf("drop-in comments = %q", got)
+	}
+	if v, _ := files[2].Value("Service", "Restart"); v != "always" {
+		t.Errorf("Restart = %q", v)
+	}
+	// A comment after a blank line which merely starts with a path is
+	// part of the drop-in.
+	if v, _ := files[2].Value("Service", "ExecStartPre"); v != "/bin/true" {
+		t.Errorf("ExecStartPre = %q", v)
+	}
+
+	if _, err := ParseCat("[Unit]\n"); !errors.Is(err, ErrSyntax) {
+		t.Errorf("error is %v, but should have been %v", err, ErrSyntax)
+	}
+}
@@ -0,0 +1,67 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListUnitFiles(t *testing.T) {
+	logFile := fakeSystemctl(t, `printf 'nginx.service enabled enabled\nnginx.socket  disabled enabled\n'`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	lopts := ListUnitFilesOptions{
+		States:   []UnitFileState{UnitFileEnabled, UnitFileDisabled},
+		Types:    []string{"service", "socket"},
+		Patterns: []string{"nginx*"},
+	}
+	files, err := ListUnitFiles(ctx, lopts, Options{})
+	if err != nil {
+		t.Fatalf("ListUnitFiles returned error: %v", err)
+	}
+	want := []UnitFile{
+		{Name: "nginx.service", State: UnitFileEnabled, Preset: "enabled"},
+		{Name: "nginx.socket", State: UnitFileDisabled, Preset: "enabled"},
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("ListUnitFiles = %+v, want %+v", files, want)
+	}
+	wantArgs := [][]string{{"list-unit-files", "--system", "nginx*", "--no-legend", "--full", "--no-pager", "--state=enabled,disabled", "--type=service,socket"}}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
+
+func TestIsMaskedSuffix(t *testing.T) {
+	logFile := fakeSystemctl(t, `printf 'foo.socket masked enabled\nbar.service masked-runtime -\n'`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tests := []struct {
+		unit string
+		want bool
+	}{
+		{"foo", false},
+		{"foo.service", false},
+		{"foo.socket", true},
+		{"bar", true},
+		{"bar.service", true},
+	}
+	for _, tt := range tests {
+		got, err := IsMasked(ctx, tt.unit, Options{})
+		if err != nil {
+			t.Fatalf("IsMasked(%q) returned error: %v", tt.unit, err)
+		}
+		if got != tt.want {
+			t.Errorf("IsMasked(%q) = %v, want %v", tt.unit, got, tt.want)
+		}
+	}
+	wantArgs := []string{"list-unit-files", "--system", "--no-legend", "--full", "--no-pager", "--state=masked,masked-runtime"}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got[0], wantArgs) {
+		t.Errorf("invocation = %v, want %v", got[0], wantArgs)
+	}
+}
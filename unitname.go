@@ -0,0 +1,214 @@
+package systemctl
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// unitNameMax is the maximum length of a unit name, see systemd.unit(5).
+const unitNameMax = 255
+
+// UnitName is a unit name split into its parts, see systemd.unit(5).
+//
+//	nginx.service        Prefix "nginx", Type "service"
+//	getty@.service       Prefix "getty", Template, Type "service"
+//	getty@tty1.service   Prefix "getty", Template, Instance "tty1", Type "service"
+type UnitName struct {
+	Prefix string
+	// Template is set for template units and their instances, whose name
+	// contains an "@".
+	Template bool
+	// Instance is the (escaped) instance name of a template instance, or
+	// empty for template units themselves.
+	Instance string
+	Type     string
+}
+
+// ParseUnitName parses and validates a unit name. ErrInvalidName is
+// returned if the name is too long, contains characters which are not
+// allowed, or does not end in a known unit type.
+func ParseUnitName(name string) (UnitName, error) {
+	invalid := func(reason string) (UnitName, error) {
+		return UnitName{}, fmt.Errorf("%q %s: %w", name, reason, ErrInvalidName)
+	}
+	if name == "" {
+		return invalid("is empty")
+	}
+	if len(name) > unitNameMax {
+		return invalid(fmt.Sprintf("is longer than %d characters", unitNameMax))
+	}
+	dot := strings.LastIndexByte(name, '.')
+	if dot < 0 {
+		return invalid("has no unit type suffix")
+	}
+	var u UnitName
+	u.Type = name[dot+1:]
+	if !slices.Contains(UnitTypes, u.Type) {
+		return invalid("has an unknown unit type")
+	}
+	u.Prefix, u.Instance, u.Template = strings.Cut(name[:dot], "@")
+	if u.Prefix == "" {
+		return invalid("has an empty prefix")
+	}
+	if !validUnitChars(u.Prefix, false) {
+		return invalid("contains invalid characters")
+	}
+	if !validUnitChars(u.Instance, true) {
+		return invalid("contains invalid characters in its instance")
+	}
+	return u, nil
+}
+
+// ValidateUnitName checks a unit name against the unit name grammar of
+// systemd.unit(5), returning ErrInvalidName if it doesn't match.
+func ValidateUnitName(name string) error {
+	_, err := ParseUnitName(name)
+	return err
+}
+
+// validUnitChars reports whether s only contains characters allowed in
+// unit names: ASCII letters and digits, ":", "-", "_", "." and "\".
+// Instances may contain "@" as well.
+func validUnitChars(s string, instance bool) bool {
+	for i := 0; i < len(s); i++ {
+		if !isUnitChar(s[i]) && (!instance || s[i] != '@') {
+			return false
+		}
+	}
+	return true
+}
+
+func isUnitChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == ':' || c == '-' || c == '_' || c == '.' || c == '\\'
+}
+
+// String returns the unit name.
+func (u UnitName) String() string {
+	if u.Template {
+		return u.Prefix + "@" + u.Instance + "." + u.Type
+	}
+	return u.Prefix + "." + u.Type
+}
+
+// IsTemplate reports whether u is a template unit such as getty@.service.
+func (u UnitName) IsTemplate() bool {
+	return u.Template && u.Instance == ""
+}
+
+// IsInstance reports whether u is an instance of a template unit, such as
+// getty@tty1.service.
+func (u UnitName) IsInstance() bool {
+	return u.Template && u.Instance != ""
+}
+
+// TemplateName returns the template an instance was created from, e.g.
+// getty@.service for getty@tty1.service.
+func (u UnitName) TemplateName() UnitName {
+	u.Instance = ""
+	return u
+}
+
+// Instantiate returns the instance of the template u for the given
+// instance string, which is escaped first, like
+// `systemd-escape --template=u instance`.
+func (u UnitName) Instantiate(instance string) (UnitName, error) {
+	if !u.Template {
+		return UnitName{}, fmt.Errorf("%q is not a template: %w", u.String(), ErrInvalidName)
+	}
+	u.Instance = EscapeString(instance)
+	if err := ValidateUnitName(u.String()); err != nil {
+		return UnitName{}, err
+	}
+	return u, nil
+}
+
+// EscapeString escapes a string for use in a unit name, like
+// `systemd-escape`. "/" becomes "-", and all characters other than ASCII
+// letters, digits, ":", "_" and a non-leading "." are written as "\xNN".
+func EscapeString(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '/':
+			b.WriteByte('-')
+		case c == '.' && i > 0, c != '-' && c != '\\' && c != '.' && isUnitChar(c):
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		}
+	}
+	return b.String()
+}
+
+// UnescapeString reverses EscapeString, like `systemd-escape --unescape`.
+func UnescapeString(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '-':
+			b.WriteByte('/')
+		case '\\':
+			if i+3 >= len(s) || s[i+1] != 'x' {
+				return "", fmt.Errorf("%q has an invalid escape sequence: %w", s, ErrInvalidName)
+			}
+			n, err := strconv.ParseUint(s[i+2:i+4], 16, 8)
+			if err != nil {
+				return "", fmt.Errorf("%q has an invalid escape sequence: %w", s, ErrInvalidName)
+			}
+			b.WriteByte(byte(n))
+			i += 3
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+// EscapePath escapes a file system path for use in a unit name, like
+// `systemd-escape --path`. The path is cleaned and its leading and
+// trailing slashes are dropped, and the root directory becomes "-".
+func EscapePath(p string) string {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if p == "" {
+		return "-"
+	}
+	return EscapeString(p)
+}
+
+// UnescapePath reverses EscapePath, like
+// `systemd-escape --unescape --path`, returning an absolute path.
+func UnescapePath(s string) (string, error) {
+	if s == "-" {
+		return "/", nil
+	}
+	p, err := UnescapeString(s)
+	if err != nil {
+		return "", err
+	}
+	return "/" + p, nil
+}
+
+// PathToUnit returns the name of the unit of the given type for a path,
+// like `systemd-escape --path --suffix=<type>`. For example, the mount
+// unit for /var/lib/docker is var-lib-docker.mount.
+func PathToUnit(p string, unitType string) (string, error) {
+	name := EscapePath(p) + "." + unitType
+	if err := ValidateUnitName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// withDefaultType appends the given type suffix to a unit name which has
+// none, as systemctl does for bare names.
+func withDefaultType(unit string, unitType string) string {
+	if HasValidUnitSuffix(unit) {
+		return unit
+	}
+	return unit + "." + unitType
+}
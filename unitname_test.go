@@ -0,0 +1,148 @@
+package systemctl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseUnitName(t *testing.T) {
+	tests := []struct {
+		name string
+		want UnitName
+	}{
+		{"nginx.service", UnitName{Prefix: "nginx", Type: "service"}},
+		{"getty@.service", UnitName{Prefix: "getty", Template: true, Type: "service"}},
+		{"getty@tty1.service", UnitName{Prefix: "getty", Template: true, Instance: "tty1", Type: "service"}},
+		{"foo.bar.socket", UnitName{Prefix: "foo.bar", Type: "socket"}},
+		{`systemd-fsck@dev-disk-by\x2duuid-1234.service`, UnitName{Prefix: "systemd-fsck", Template: true, Instance: `dev-disk-by\x2duuid-1234`, Type: "service"}},
+		{"user@1000@x.service", UnitName{Prefix: "user", Template: true, Instance: "1000@x", Type: "service"}},
+		{"-.mount", UnitName{Prefix: "-", Type: "mount"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseUnitName(tt.name)
+		if err != nil {
+			t.Errorf("ParseUnitName(%q) returned error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseUnitName(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+		if got.String() != tt.name {
+			t.Errorf("ParseUnitName(%q).String() = %q", tt.name, got.String())
+		}
+	}
+
+	for _, name := range []string{
+		"",
+		"nginx",
+		"nginx.conf",
+		".service",
+		"@tty1.service",
+		"foo bar.service",
+		"--now",
+		"-H evil.service",
+		"naïve.service",
+		"foo@bar baz.service",
+		strings.Repeat("a", 248) + ".service",
+	} {
+		if _, err := ParseUnitName(name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("ParseUnitName(%q) error is %v, but should have been %v", name, err, ErrInvalidName)
+		}
+	}
+	if err := ValidateUnitName(strings.Repeat("a", 247) + ".service"); err != nil {
+		t.Errorf("ValidateUnitName(255 characters) returned error: %v", err)
+	}
+}
+
+func TestUnitNameTemplates(t *testing.T) {
+	instance, _ := ParseUnitName("getty@tty1.service")
+	if !instance.IsInstance() || instance.IsTemplate() {
+		t.Errorf("getty@tty1.service: IsInstance = %v, IsTemplate = %v", instance.IsInstance(), instance.IsTemplate())
+	}
+	template := instance.TemplateName()
+	if template.String() != "getty@.service" || !template.IsTemplate() {
+		t.Errorf("TemplateName = %q", template)
+	}
+
+	// From systemd-escape(1).
+	nspawn, _ := ParseUnitName("systemd-nspawn@.service")
+	got, err := nspawn.Instantiate("My Container 1")
+	if err != nil {
+		t.Fatalf("Instantiate returned error: %v", err)
+	}
+	if want := `systemd-nspawn@My\x20Container\x201.service`; got.String() != want {
+		t.Errorf("Instantiate = %q, want %q", got, want)
+	}
+
+	plain, _ := ParseUnitName("nginx.service")
+	if _, err := plain.Instantiate("x"); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Instantiate on a plain unit error is %v, but should have been %v", err, ErrInvalidName)
+	}
+}
+
+func TestEscapeString(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		// From systemd-escape(1).
+		{"Hallöchen, Meister", `Hall\xc3\xb6chen\x2c\x20Meister`},
+		{"tmp/waldi/foobar", "tmp-waldi-foobar"},
+		{"foo-bar", `foo\x2dbar`},
+		{".hidden.file", `\x2ehidden.file`},
+		{`back\slash`, `back\x5cslash`},
+		{"a:b_c", "a:b_c"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got := EscapeString(tt.value)
+		if got != tt.want {
+			t.Errorf("EscapeString(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+		back, err := UnescapeString(got)
+		if err != nil {
+			t.Errorf("UnescapeString(%q) returned error: %v", got, err)
+		} else if back != tt.value {
+			t.Errorf("UnescapeString(%q) = %q, want %q", got, back, tt.value)
+		}
+	}
+
+	for _, value := range []string{`\x2`, `\y20`, `\xzz`, `trailing\`} {
+		if _, err := UnescapeString(value); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("UnescapeString(%q) error is %v, but should have been %v", value, err, ErrInvalidName)
+		}
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+		back string
+	}{
+		// From systemd-escape(1).
+		{"/tmp//waldi/foobar/", "tmp-waldi-foobar", "/tmp/waldi/foobar"},
+		{"/", "-", "/"},
+		{"/dev/disk/by-label/My Disk", `dev-disk-by\x2dlabel-My\x20Disk`, "/dev/disk/by-label/My Disk"},
+		{"var/lib/docker", "var-lib-docker", "/var/lib/docker"},
+	}
+	for _, tt := range tests {
+		got := EscapePath(tt.path)
+		if got != tt.want {
+			t.Errorf("EscapePath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+		back, err := UnescapePath(got)
+		if err != nil || back != tt.back {
+			t.Errorf("UnescapePath(%q) = %q, %v, want %q", got, back, err, tt.back)
+		}
+	}
+
+	unit, err := PathToUnit("/tmp//waldi/foobar/", "mount")
+	if err != nil || unit != "tmp-waldi-foobar.mount" {
+		t.Errorf("PathToUnit = %q, %v, want tmp-waldi-foobar.mount", unit, err)
+	}
+	if _, err := PathToUnit("/srv", "bogus"); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("PathToUnit with a bad type error is %v, but should have been %v", err, ErrInvalidName)
+	}
+}
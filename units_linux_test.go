@@ -0,0 +1,53 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListUnits(t *testing.T) {
+	logFile := fakeSystemctl(t, `echo '[{"unit":"nginx.service","load":"loaded","active":"failed","sub":"failed","description":"nginx"}]'`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	lopts := ListUnitsOptions{
+		Types:    []string{"service", "socket"},
+		States:   []string{"failed"},
+		Patterns: []string{"nginx*", "apache*"},
+	}
+	units, err := ListUnits(ctx, lopts, Options{UserMode: true})
+	if err != nil {
+		t.Fatalf("ListUnits returned error: %v", err)
+	}
+	want := []Unit{{Name: "nginx.service", Load: "loaded", Active: "failed", Sub: "failed", Description: "nginx"}}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("ListUnits = %+v, want %+v", units, want)
+	}
+	wantArgs := [][]string{{"list-units", "--user", "nginx*", "apache*", "--full", "--no-pager", "--output=json", "--type=service,socket", "--state=failed"}}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
+
+func TestListUnitsTextFallback(t *testing.T) {
+	logFile := fakeSystemctl(t, `printf '  UNIT          LOAD   ACTIVE SUB    DESCRIPTION\n● nginx.service loaded failed failed nginx\n\n1 loaded units listed.\n'`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	units, err := ListUnits(ctx, ListUnitsOptions{Failed: true, All: true}, Options{})
+	if err != nil {
+		t.Fatalf("ListUnits returned error: %v", err)
+	}
+	want := []Unit{{Name: "nginx.service", Load: "loaded", Active: "failed", Sub: "failed", Description: "nginx"}}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("ListUnits = %+v, want %+v", units, want)
+	}
+	wantArgs := [][]string{{"list-units", "--system", "--full", "--no-pager", "--output=json", "--all", "--failed"}}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
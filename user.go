@@ -0,0 +1,60 @@
+package systemctl
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+const machineFlag = "--machine="
+
+var (
+	// runtimeDirRoot is the parent of each user's $XDG_RUNTIME_DIR.
+	runtimeDirRoot = "/run/user"
+	// lingerDir holds one empty file per user with lingering enabled.
+	lingerDir = "/var/lib/systemd/linger"
+)
+
+// userRuntimeDir returns the $XDG_RUNTIME_DIR of the given user
+// (/run/user/UID), resolving the user's UID from the account database.
+func userRuntimeDir(username string) (string, error) {
+	u, err := user.Lookup(username)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(runtimeDirRoot, u.Uid), nil
+}
+
+// machineUser extracts the user name from a `--machine=<user>@.host`
+// argument as produced by prepareArgs, or returns an empty string.
+func machineUser(args []string) string {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, machineFlag) {
+			continue
+		}
+		name, ok := strings.CutSuffix(strings.TrimPrefix(arg, machineFlag), "@.host")
+		if ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// checkUserManager explains a bus failure when targeting another user's
+// manager: the user may not exist, or their manager may not be running
+// because they are logged out and lingering is disabled.
+func checkUserManager(username string) error {
+	dir, err := userRuntimeDir(username)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(dir, "systemd", "private")); err == nil {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(lingerDir, username)); err == nil {
+		return fmt.Errorf("user manager for %s is not running yet: %w", username, ErrUserManagerNotRunning)
+	}
+	return fmt.Errorf("user manager for %s is not running and lingering is disabled: %w", username, ErrUserManagerNotRunning)
+}
@@ -0,0 +1,64 @@
+package systemctl
+
+import (
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+)
+
+func TestMachineUser(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "no machine flag", args: []string{"start", "--user", "foo.service"}, want: ""},
+		{name: "user machine", args: []string{"start", "--user", "--machine=alice@.host", "foo.service"}, want: "alice"},
+		{name: "remote machine", args: []string{"start", "--user", "--machine=container"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := machineUser(tt.args); got != tt.want {
+				t.Errorf("machineUser(%v) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckUserManager(t *testing.T) {
+	cur, err := user.Current()
+	if err != nil {
+		t.Skip("cannot determine current user")
+	}
+	tempDir := t.TempDir()
+	originalRuntime, originalLinger := runtimeDirRoot, lingerDir
+	runtimeDirRoot = filepath.Join(tempDir, "run")
+	lingerDir = filepath.Join(tempDir, "linger")
+	t.Cleanup(func() {
+		runtimeDirRoot, lingerDir = originalRuntime, originalLinger
+	})
+
+	err = checkUserManager(cur.Username)
+	if !errors.Is(err, ErrUserManagerNotRunning) {
+		t.Fatalf("error is %v, but should have been %v", err, ErrUserManagerNotRunning)
+	}
+
+	privateDir := filepath.Join(runtimeDirRoot, cur.Uid, "systemd")
+	if err := os.MkdirAll(privateDir, 0o755); err != nil {
+		t.Fatalf("create runtime dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(privateDir, "private"), nil, 0o644); err != nil {
+		t.Fatalf("create private socket placeholder: %v", err)
+	}
+	if err := checkUserManager(cur.Username); err != nil {
+		t.Fatalf("error is %v, but should have been nil", err)
+	}
+
+	var unknown user.UnknownUserError
+	if err := checkUserManager("nonexistant-user"); !errors.As(err, &unknown) {
+		t.Errorf("error is %v, but should have been an unknown user error", err)
+	}
+}
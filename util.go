@@ -9,7 +9,11 @@ import (
 	"strings"
 )
 
-var systemctl string
+var (
+	systemctl  string
+	loginctl   string
+	systemdRun string
+)
 
 // killed is the exit code returned when a process is terminated by SIGINT.
 const killed = 130
@@ -17,9 +21,30 @@ const killed = 130
 func init() {
 	path, _ := exec.LookPath("systemctl")
 	systemctl = path
+	path, _ = exec.LookPath("loginctl")
+	loginctl = path
+	path, _ = exec.LookPath("systemd-run")
+	systemdRun = path
 }
 
 func execute(ctx context.Context, args []string) (string, string, int, error) {
+	if systemctl == "" {
+		return "", "", 1, ErrNotInstalled
+	}
+	return executeCommand(ctx, systemctl, args)
+}
+
+// executeCommand runs the given systemd binary and classifies its stderr
+// output the same way for every tool.
+func executeCommand(ctx context.Context, bin string, args []string) (string, string, int, error) {
+	// Options.User is passed as --machine=<user>@.host, which older
+	// versions don't understand.
+	if machineUser(args) != "" {
+		if err := requireVersion(ctx, versionMachineUser, "Options.User"); err != nil {
+			return "", "", 1, err
+		}
+	}
+
 	var (
 		err      error
 		stderr   bytes.Buffer
@@ -29,10 +54,7 @@ func execute(ctx context.Context, args []string) (string, string, int, error) {
 		warnings string
 	)
 
-	if systemctl == "" {
-		return "", "", 1, ErrNotInstalled
-	}
-	cmd := exec.CommandContext(ctx, systemctl, args...)
+	cmd := exec.CommandContext(ctx, bin, args...)
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 	err = cmd.Run()
@@ -51,6 +73,13 @@ func execute(ctx context.Context, args []string) (string, string, int, error) {
 	if code != 0 && err == nil {
 		err = fmt.Errorf("received error code %d for stderr `%s`: %w", code, warnings, ErrUnspecified)
 	}
+	if errors.Is(err, ErrBusFailure) {
+		if name := machineUser(args); name != "" {
+			if userErr := checkUserManager(name); userErr != nil {
+				err = errors.Join(userErr, err)
+			}
+		}
+	}
 
 	return output, warnings, code, err
 }
@@ -58,17 +87,25 @@ func execute(ctx context.Context, args []string) (string, string, int, error) {
 // prepareArgs builds the systemctl command arguments from a base command,
 // options, and any additional arguments the caller wants to pass through.
 func prepareArgs(base string, opts Options, extra ...string) []string {
-	args := make([]string, 0, 2+len(extra))
+	args := make([]string, 0, 3+len(extra))
 	args = append(args, base)
-	if opts.UserMode {
-		args = append(args, "--user")
-	} else {
-		args = append(args, "--system")
-	}
+	args = append(args, managerArgs(opts)...)
 	args = append(args, extra...)
 	return args
 }
 
+// managerArgs returns the flags selecting the service manager to talk to.
+// They are understood by systemctl and systemd-run alike.
+func managerArgs(opts Options) []string {
+	if !opts.UserMode {
+		return []string{"--system"}
+	}
+	if opts.User != "" {
+		return []string{"--user", machineFlag + opts.User + "@.host"}
+	}
+	return []string{"--user"}
+}
+
 func filterErr(stderr string) error {
 	// Order matters: check higher-priority errors first.
 	// For example, `systemctl mask nginx` as a non-root user on a system
@@ -85,6 +122,8 @@ func filterErr(stderr string) error {
 		return errors.Join(ErrBusFailure, fmt.Errorf("stderr: %s", stderr))
 	case strings.Contains(stderr, `Failed to connect to bus`):
 		return errors.Join(ErrBusFailure, fmt.Errorf("stderr: %s", stderr))
+	case strings.Contains(stderr, `may not be isolated`):
+		return errors.Join(ErrIsolateNotAllowed, fmt.Errorf("stderr: %s", stderr))
 	case strings.Contains(stderr, `is masked`):
 		return errors.Join(ErrMasked, fmt.Errorf("stderr: %s", stderr))
 	case strings.Contains(stderr, `does not exist`):
@@ -64,17 +64,89 @@ func TestPrepareArgs(t *testing.T) {
 func TestParseMaskedUnits(t *testing.T) {
 	stdout := `UNIT FILE                         STATE  PRESET
 foo.service                       masked enabled
+foo.socket                        disabled enabled
 bar-baz.timer                     disabled enabled
 foo.bar.service                   masked enabled
 quux@one.service                  masked -
+run.service                       masked-runtime -
 	tabbed.service	masked	enabled
 
-4 unit files listed.`
+6 unit files listed.`
 
-	got := parseMaskedUnits(stdout)
-	expected := []string{"foo", "foo.bar", "quux@one", "tabbed"}
+	got := maskedUnits(parseUnitFiles(stdout))
+	expected := []string{"foo.service", "foo.bar.service", "quux@one.service", "run.service", "tabbed.service"}
 	if !reflect.DeepEqual(got, expected) {
-		t.Fatalf("parseMaskedUnits() = %v, want %v", got, expected)
+		t.Fatalf("maskedUnits() = %v, want %v", got, expected)
+	}
+}
+
+func TestParseUnitFiles(t *testing.T) {
+	stdout := "foo.service enabled enabled\nfoo.socket masked disabled\nbar@.service indirect -\nold.timer static\n"
+	got := parseUnitFiles(stdout)
+	expected := []UnitFile{
+		{Name: "foo.service", State: UnitFileEnabled, Preset: "enabled"},
+		{Name: "foo.socket", State: UnitFileMasked, Preset: "disabled"},
+		{Name: "bar@.service", State: UnitFileIndirect},
+		{Name: "old.timer", State: UnitFileStatic},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("parseUnitFiles() = %+v, want %+v", got, expected)
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	stdout := `  UNIT                      LOAD      ACTIVE   SUB     JOB   DESCRIPTION
+  cron.service              loaded    active   running       Regular background program processing daemon
+● nginx.service             loaded    failed   failed        A high performance web server
+  postgresql.service        loaded    inactive dead    start PostgreSQL RDBMS
+○ missing.service           not-found inactive dead          missing.service
+
+LOAD   = Reflects whether the unit definition was properly loaded.
+ACTIVE = The high-level unit activation state, i.e. generalization of SUB.
+SUB    = The low-level unit activation state, values depend on unit type.
+JOB    = Pending job for the unit.
+
+4 loaded units listed.`
+
+	got := parseUnits(stdout)
+	expected := []Unit{
+		{Name: "cron.service", Load: "loaded", Active: "active", Sub: "running", Description: "Regular background program processing daemon"},
+		{Name: "nginx.service", Load: "loaded", Active: "failed", Sub: "failed", Description: "A high performance web server"},
+		{Name: "postgresql.service", Load: "loaded", Active: "inactive", Sub: "dead", Job: "start", Description: "PostgreSQL RDBMS"},
+		{Name: "missing.service", Load: "not-found", Active: "inactive", Sub: "dead", Description: "missing.service"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("parseUnits() = %+v, want %+v", got, expected)
+	}
+
+	// Without the legend, as printed with --no-legend.
+	got = parseUnits("● nginx.service loaded failed failed A high performance web server\n  cron.service loaded active running Cron\n")
+	expected = []Unit{
+		{Name: "nginx.service", Load: "loaded", Active: "failed", Sub: "failed", Description: "A high performance web server"},
+		{Name: "cron.service", Load: "loaded", Active: "active", Sub: "running", Description: "Cron"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("parseUnits(no legend) = %+v, want %+v", got, expected)
+	}
+
+	if got := parseUnits("0 loaded units listed.\n"); len(got) != 0 {
+		t.Fatalf("parseUnits(empty) = %+v, want none", got)
+	}
+}
+
+func TestParseUnitsJSON(t *testing.T) {
+	stdout := `[{"unit":"cron.service","load":"loaded","active":"active","sub":"running","job":null,"description":"Cron"},` +
+		`{"unit":"db.service","load":"loaded","active":"inactive","sub":"dead","job":"start","description":"Database"}]`
+	got, err := parseUnitsJSON(stdout)
+	if err != nil {
+		t.Fatalf("parseUnitsJSON() returned error: %v", err)
+	}
+	expected := []Unit{
+		{Name: "cron.service", Load: "loaded", Active: "active", Sub: "running", Description: "Cron"},
+		{Name: "db.service", Load: "loaded", Active: "inactive", Sub: "dead", Job: "start", Description: "Database"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("parseUnitsJSON() = %+v, want %+v", got, expected)
 	}
 }
 
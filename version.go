@@ -0,0 +1,123 @@
+package systemctl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/taigrr/systemctl/properties"
+)
+
+// The systemd versions which introduced the features the library uses.
+const (
+	versionJSONOutput  = 246
+	versionMachineUser = 248
+	versionMemoryZSwap = 253
+	versionKillValue   = 254
+	versionListPaths   = 254
+)
+
+// propertyVersions lists properties which older systemd versions don't
+// know about, and would silently leave empty.
+var propertyVersions = map[properties.Property]int{
+	properties.MemoryZSwapMax:        versionMemoryZSwap,
+	properties.StartupMemoryZSwapMax: versionMemoryZSwap,
+}
+
+// SystemdVersion is the version of systemctl, as printed by
+// `systemctl --version`.
+type SystemdVersion struct {
+	// Major is the systemd release, e.g. 255.
+	Major int
+	// Version is the full version including the distribution's suffix,
+	// e.g. "255.4-1ubuntu8".
+	Version string
+	// Features lists the compile-time features, e.g. "+SELINUX" for
+	// enabled and "-APPARMOR" for disabled ones.
+	Features []string
+}
+
+// AtLeast reports whether v is the given systemd release or newer.
+func (v SystemdVersion) AtLeast(major int) bool {
+	return v.Major >= major
+}
+
+// HasFeature reports whether systemd was built with the given feature,
+// e.g. "SELINUX".
+func (v SystemdVersion) HasFeature(feature string) bool {
+	return slices.Contains(v.Features, "+"+strings.TrimPrefix(feature, "+"))
+}
+
+var (
+	versionMu sync.Mutex
+	// version caches the result of GetSystemdVersion once detected.
+	version *SystemdVersion
+)
+
+// GetSystemdVersion returns the version and features of systemctl. It is
+// detected on first use and cached afterwards; failures are not cached.
+func GetSystemdVersion(ctx context.Context) (SystemdVersion, error) {
+	versionMu.Lock()
+	defer versionMu.Unlock()
+	if version != nil {
+		return *version, nil
+	}
+	stdout, _, _, err := execute(ctx, []string{"--version"})
+	if err != nil {
+		return SystemdVersion{}, err
+	}
+	v, err := parseSystemdVersion(stdout)
+	if err != nil {
+		return SystemdVersion{}, err
+	}
+	version = &v
+	return v, nil
+}
+
+// parseSystemdVersion parses the output of `systemctl --version`:
+//
+//	systemd 255 (255.4-1ubuntu8)
+//	+PAM +AUDIT +SELINUX -APPARMOR ... default-hierarchy=unified
+func parseSystemdVersion(stdout string) (SystemdVersion, error) {
+	first, rest, _ := strings.Cut(stdout, "\n")
+	fields := strings.Fields(first)
+	if len(fields) < 2 || fields[0] != "systemd" {
+		return SystemdVersion{}, fmt.Errorf("unexpected version output %q: %w", first, ErrUnspecified)
+	}
+	major, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return SystemdVersion{}, fmt.Errorf("unexpected version output %q: %w", first, ErrUnspecified)
+	}
+	v := SystemdVersion{Major: major, Version: fields[1], Features: strings.Fields(rest)}
+	if len(fields) > 2 {
+		v.Version = strings.Trim(fields[2], "()")
+	}
+	return v, nil
+}
+
+// requireVersion returns ErrUnsupportedBySystemd if systemd is known to be
+// older than the given release. If the version can't be detected, the
+// feature is assumed to be supported and left to systemctl to reject.
+func requireVersion(ctx context.Context, major int, feature string) error {
+	v, err := GetSystemdVersion(ctx)
+	if err != nil || v.Major == 0 || v.AtLeast(major) {
+		return nil
+	}
+	return fmt.Errorf("%s requires systemd %d, found %d: %w", feature, major, v.Major, ErrUnsupportedBySystemd)
+}
+
+// requirePropertyVersions calls requireVersion for each property which
+// needs a newer systemd.
+func requirePropertyVersions(ctx context.Context, props ...properties.Property) error {
+	for _, p := range props {
+		if major, ok := propertyVersions[p]; ok {
+			if err := requireVersion(ctx, major, string(p)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+//go:build linux
+
+package systemctl
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/taigrr/systemctl/properties"
+)
+
+func TestGetSystemdVersion(t *testing.T) {
+	logFile := fakeSystemctl(t, `if [ "$1" = --version ]; then
+	printf 'systemd 252 (252.22-1~deb12u1)\n+PAM +SELINUX -APPARMOR\n'
+fi`)
+	version = nil
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for range 2 {
+		v, err := GetSystemdVersion(ctx)
+		if err != nil {
+			t.Fatalf("GetSystemdVersion returned error: %v", err)
+		}
+		if v.Major != 252 || v.Version != "252.22-1~deb12u1" || !v.HasFeature("SELINUX") {
+			t.Errorf("GetSystemdVersion = %+v", v)
+		}
+	}
+	wantArgs := [][]string{{"--version"}}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
+
+func TestUnsupportedBySystemd(t *testing.T) {
+	logFile := fakeSystemctl(t, `exit 0`)
+	fakeSystemdVersion(t, &SystemdVersion{Major: 239, Version: "239-78.el8"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := ListPaths(ctx, Options{}); !errors.Is(err, ErrUnsupportedBySystemd) {
+		t.Errorf("ListPaths error is %v, but should have been %v", err, ErrUnsupportedBySystemd)
+	}
+	if _, err := Show(ctx, "nginx", properties.MemoryZSwapMax, Options{}); !errors.Is(err, ErrUnsupportedBySystemd) {
+		t.Errorf("Show(MemoryZSwapMax) error is %v, but should have been %v", err, ErrUnsupportedBySystemd)
+	}
+	if _, err := ShowUnits(ctx, []string{"nginx"}, []properties.Property{properties.MemoryZSwapMax}, Options{}); !errors.Is(err, ErrUnsupportedBySystemd) {
+		t.Errorf("ShowUnits(MemoryZSwapMax) error is %v, but should have been %v", err, ErrUnsupportedBySystemd)
+	}
+	if _, err := Show(ctx, "nginx", properties.StartupMemoryZSwapMax, Options{}); !errors.Is(err, ErrUnsupportedBySystemd) {
+		t.Errorf("Show(StartupMemoryZSwapMax) error is %v, but should have been %v", err, ErrUnsupportedBySystemd)
+	}
+	if err := Kill(ctx, "nginx", Options{}, "--kill-whom=main", "--kill-value=1"); !errors.Is(err, ErrUnsupportedBySystemd) {
+		t.Errorf("Kill(--kill-value=) error is %v, but should have been %v", err, ErrUnsupportedBySystemd)
+	}
+	if err := Kill(ctx, "nginx", Options{}, "--kill-value", "1"); !errors.Is(err, ErrUnsupportedBySystemd) {
+		t.Errorf("Kill(--kill-value) error is %v, but should have been %v", err, ErrUnsupportedBySystemd)
+	}
+	if _, err := IsActive(ctx, "app", Options{UserMode: true, User: "root"}); !errors.Is(err, ErrUnsupportedBySystemd) {
+		t.Errorf("IsActive(User) error is %v, but should have been %v", err, ErrUnsupportedBySystemd)
+	}
+	// JSON output is optional, so ListUnits falls back to text right away.
+	if _, err := ListUnits(ctx, ListUnitsOptions{}, Options{}); err != nil {
+		t.Errorf("ListUnits returned error: %v", err)
+	}
+
+	wantArgs := [][]string{{"list-units", "--system", "--full", "--no-pager"}}
+	if got := fakeInvocations(t, logFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("invocations = %v, want %v", got, wantArgs)
+	}
+}
@@ -0,0 +1,61 @@
+package systemctl
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseSystemdVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   SystemdVersion
+	}{
+		{
+			name:   "distribution version",
+			output: "systemd 255 (255.4-1ubuntu8)\n+PAM +AUDIT +SELINUX -APPARMOR default-hierarchy=unified\n",
+			want:   SystemdVersion{Major: 255, Version: "255.4-1ubuntu8", Features: []string{"+PAM", "+AUDIT", "+SELINUX", "-APPARMOR", "default-hierarchy=unified"}},
+		},
+		{
+			name:   "rhel",
+			output: "systemd 239 (239-78.el8)\n+PAM +AUDIT\n",
+			want:   SystemdVersion{Major: 239, Version: "239-78.el8", Features: []string{"+PAM", "+AUDIT"}},
+		},
+		{
+			name:   "no full version",
+			output: "systemd 237\n+PAM\n",
+			want:   SystemdVersion{Major: 237, Version: "237", Features: []string{"+PAM"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSystemdVersion(tt.output)
+			if err != nil {
+				t.Fatalf("parseSystemdVersion returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSystemdVersion = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	for _, output := range []string{"", "systemctl 255\n", "systemd two-five-five\n"} {
+		if _, err := parseSystemdVersion(output); !errors.Is(err, ErrUnspecified) {
+			t.Errorf("parseSystemdVersion(%q) error is %v, but should have been %v", output, err, ErrUnspecified)
+		}
+	}
+}
+
+func TestSystemdVersionFeatures(t *testing.T) {
+	v := SystemdVersion{Major: 252, Features: []string{"+PAM", "+SELINUX", "-APPARMOR"}}
+	if !v.HasFeature("SELINUX") || !v.HasFeature("+PAM") {
+		t.Errorf("HasFeature should report enabled features")
+	}
+	if v.HasFeature("APPARMOR") || v.HasFeature("TPM2") {
+		t.Errorf("HasFeature should not report disabled or missing features")
+	}
+	if !v.AtLeast(252) || v.AtLeast(253) {
+		t.Errorf("AtLeast is wrong for version %d", v.Major)
+	}
+}